@@ -0,0 +1,76 @@
+package chess
+
+/*
+Responsible for:
+- exploding the pieces around captures in atomic games
+- finding checks when kings can be exploded
+*/
+
+// the capturer explodes on the captured square together with every piece next to it, pawns next to it survive
+func (b *SimpleBoard) addExplosion(move *FastMove) {
+    move.newPiece.array[1] = nil
+
+    for _, direction := range queen_directions {
+        location := b.addIndex(move.toLocation, direction)
+        piece := b.getPiece(location)
+        if piece == nil || piece.isPawn() || moveChanges(move, location) {
+            continue
+        }
+
+        move.newPiece.set(nil)
+        move.oldPiece.set(piece)
+        move.location.set(location)
+    }
+}
+
+// the from square and en passant captures are already part of the move
+func moveChanges(move *FastMove, location *Point) bool {
+    for i := 0; i < move.location.count; i++ {
+        if move.location.at(i) == location {
+            return true
+        }
+    }
+
+    return false
+}
+
+func adjacent(location1 *Point, location2 *Point) bool {
+    dx := location1.x - location2.x
+    dy := location1.y - location2.y
+
+    return dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1
+}
+
+// a missing king exploded, which is as bad as being in check
+// a king next to the enemy king can't be captured by that enemy without exploding both
+// players without a king can't capture anymore, so exploding the enemy king is legal even while in check
+func (b *SimpleBoard) atomicCheck(color int) bool {
+    king := b.kingLocations[color]
+    if king == nil {
+        return true
+    }
+
+    start := b.vulnerableStarts[color]
+    end := b.vulnerableEnds[color]
+
+    for i := 0; i < b.players; i++ {
+        enemyKing := b.kingLocations[i]
+        if b.allied(i, color) || enemyKing == nil {
+            continue
+        }
+        captureMoves := &b.captureMoves[i]
+
+        for j := 0; j < captureMoves.count; j++ {
+            to := captureMoves.array[j].toLocation
+
+            if to == king && !adjacent(king, enemyKing) {
+                return true
+            }
+            if start != nil && end != nil && to.y >= start.y && to.y <= end.y && to.x >= start.x && to.x <= end.x {
+                return true
+            }
+        }
+    }
+
+    return false
+}
@@ -0,0 +1,152 @@
+package chess
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_Atomic_Explosion(t *testing.T) {
+    white := 0
+    black := 1
+
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Atomic": true,
+        "Pieces": [
+            {"X": 4, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 0, "Y": 4, "Color": 0, "Type": "R", "Moved": true},
+            {"X": 4, "Y": 5, "Color": 0, "Type": "N"},
+            {"X": 4, "Y": 4, "Color": 1, "Type": "N"},
+            {"X": 3, "Y": 3, "Color": 1, "Type": "B"},
+            {"X": 5, "Y": 3, "Color": 1, "Type": "P", "Orientation": "D", "Moved": true},
+            {"X": 5, "Y": 5, "Color": 1, "Type": "Q"},
+            {"X": 3, "Y": 5, "Color": 1, "Type": "R", "Moved": true}
+        ]
+    }`)
+    b := game.getBoard()
+    hash := b.ZobristHash()
+
+    err := game.ExecuteSAN("Rxe4")
+    assert.Nil(t, err)
+
+    // the rook, the knights, the bishop, the queen, and the rook explode, the pawn survives
+    for _, location := range []Point{{0, 4}, {4, 4}, {4, 5}, {3, 3}, {5, 5}, {3, 5}} {
+        assert.Nil(t, b.getPiece(b.getIndex(location.x, location.y)))
+    }
+    assert.Equal(t, b.getAllPiece(black, PAWN_D_M), b.getPiece(b.getIndex(5, 3)))
+    assert.Equal(t, b.getAllPiece(white, KING_U_M), b.getPiece(b.getIndex(4, 7)))
+
+    err = game.Undo()
+    assert.Nil(t, err)
+    assert.Equal(t, hash, b.ZobristHash())
+    assert.Equal(t, b.getAllPiece(white, ROOK_M), b.getPiece(b.getIndex(0, 4)))
+    assert.Equal(t, b.getAllPiece(black, QUEEN), b.getPiece(b.getIndex(5, 5)))
+    assert.Equal(t, b.getAllPiece(black, ROOK_M), b.getPiece(b.getIndex(3, 5)))
+    assert.Equal(t, b.getAllPiece(white, KNIGHT), b.getPiece(b.getIndex(4, 5)))
+
+    err = game.Redo()
+    assert.Nil(t, err)
+    assert.Nil(t, b.getPiece(b.getIndex(5, 5)))
+}
+
+func Test_Atomic_Kings(t *testing.T) {
+    white := 0
+
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Atomic": true,
+        "Pieces": [
+            {"X": 4, "Y": 4, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 4, "Y": 2, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 3, "Y": 4, "Color": 1, "Type": "N"},
+            {"X": 0, "Y": 3, "Color": 1, "Type": "R", "Moved": true}
+        ]
+    }`)
+
+    moves, err := game.Moves(white)
+    assert.Nil(t, err)
+    assert.NotContains(t, moves, MoveKey{XFrom: 4, YFrom: 4, XTo: 3, YTo: 4}) // kings can't capture
+    assert.Contains(t, moves, MoveKey{XFrom: 4, YFrom: 4, XTo: 4, YTo: 3}) // the rook can't capture a king touching its own king
+
+    err = game.Execute(4, 4, 4, 3, "")
+    assert.Nil(t, err)
+    assert.False(t, game.getBoard().Check(white))
+}
+
+func Test_Atomic_ExplodeKing(t *testing.T) {
+    white := 0
+
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Atomic": true,
+        "Pieces": [
+            {"X": 7, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 6, "Y": 6, "Color": 1, "Type": "B"},
+            {"X": 3, "Y": 7, "Color": 0, "Type": "Q"},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 3, "Y": 0, "Color": 1, "Type": "R", "Moved": true}
+        ]
+    }`)
+    b := game.getBoard()
+
+    // the queen would explode her own king
+    moves, err := game.Moves(white)
+    assert.Nil(t, err)
+    assert.NotContains(t, moves, MoveKey{XFrom: 3, YFrom: 7, XTo: 6, YTo: 6})
+
+    stop := make(chan bool)
+    searcher := newParallelSearcher(b, game.getPlayerCollection(), stop)
+    moveKey, err := searcher.searchWithMinimax(2)
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: 3, YFrom: 7, XTo: 3, YTo: 0}, moveKey)
+
+    san, err := game.MoveToSAN(moveKey)
+    assert.Nil(t, err)
+    assert.Equal(t, "Qxd8#", san)
+
+    err = game.ExecuteSAN(san)
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, white, state.WinningPlayer)
+}
+
+func Test_Atomic_InsufficientMaterial(t *testing.T) {
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Atomic": true,
+        "Pieces": [
+            {"X": 4, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 1, "Y": 7, "Color": 0, "Type": "N"}
+        ]
+    }`)
+    assert.True(t, game.getBoard().InsufficientMaterial())
+
+    // disabled squares are checked before the atomic rule
+    game = newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Atomic": true,
+        "Disabled": [{"X": 0, "Y": 0}],
+        "Pieces": [
+            {"X": 4, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 1, "Y": 7, "Color": 0, "Type": "N"}
+        ]
+    }`)
+    assert.False(t, game.getBoard().InsufficientMaterial())
+}
@@ -0,0 +1,437 @@
+package chess
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+/*
+Responsible for:
+- compiling piece definitions in betza notation into move generators
+*/
+const (
+    BETZA_U = 0
+    BETZA_R = 1
+    BETZA_D = 2
+    BETZA_L = 3
+)
+
+// one step of each atom, the other directions are its rotations and reflections
+var betza_atoms = map[byte]Point{
+    'W': {1, 0},
+    'F': {1, 1},
+    'D': {2, 0},
+    'N': {2, 1},
+    'A': {2, 2},
+    'H': {3, 0},
+    'C': {3, 1},
+    'Z': {3, 2},
+    'G': {3, 3},
+}
+
+// shorthands for combinations of atoms, a doubled atom is a rider
+var betza_shorthands = map[byte]string{
+    'R': "WW",
+    'B': "FF",
+    'Q': "WWFF",
+    'K': "WF",
+}
+
+// directions are written for a piece facing up, forward is -y and right is +x
+type betzaMove struct {
+    directions [4][]*Point // rotated for each orientation
+    limit int // number of steps, 0 for unlimited
+    move bool // may move to an empty square
+    capture bool // may capture an enemy piece
+    initial bool // only before the piece moved
+    lame bool // the squares passed by a leap have to be empty
+    enPassant bool // may capture en passant
+}
+
+type betzaPiece struct {
+    moves []betzaMove
+    initial bool
+}
+
+func parseBetza(notation string) (*betzaPiece, error) {
+    piece := &betzaPiece{}
+
+    i := 0
+    for i < len(notation) {
+        start := i
+        for i < len(notation) && notation[i] >= 'a' && notation[i] <= 'z' {
+            i++
+        }
+        prefix := notation[start:i]
+
+        if i == len(notation) {
+            return nil, fmt.Errorf("invalid betza %s, missing atom", notation)
+        }
+
+        atoms := string(notation[i])
+        if shorthand, ok := betza_shorthands[notation[i]]; ok {
+            atoms = shorthand
+        } else if _, ok := betza_atoms[notation[i]]; !ok {
+            return nil, fmt.Errorf("invalid betza atom %c", notation[i])
+        } else if i + 1 < len(notation) && notation[i + 1] == notation[i] {
+            atoms += atoms
+            i++
+        }
+        i++
+
+        start = i
+        for i < len(notation) && notation[i] >= '0' && notation[i] <= '9' {
+            i++
+        }
+        limit := -1
+        if i > start {
+            limit, _ = strconv.Atoi(notation[start:i])
+        }
+
+        for j := 0; j < len(atoms); j++ {
+            rider := j + 1 < len(atoms) && atoms[j + 1] == atoms[j]
+            move, err := createBetzaMove(prefix, atoms[j], rider, limit)
+            if err != nil {
+                return nil, err
+            }
+
+            piece.moves = append(piece.moves, *move)
+            piece.initial = piece.initial || move.initial
+            if rider {
+                j++
+            }
+        }
+    }
+
+    if len(piece.moves) == 0 {
+        return nil, fmt.Errorf("invalid betza %s, no moves", notation)
+    }
+
+    return piece, nil
+}
+
+func createBetzaMove(prefix string, atom byte, rider bool, limit int) (*betzaMove, error) {
+    move := &betzaMove{
+        limit: 1,
+    }
+
+    if rider {
+        move.limit = 0
+    }
+    if limit >= 0 {
+        move.limit = limit
+    }
+
+    directionLetters := ""
+    for k := 0; k < len(prefix); k++ {
+        switch prefix[k] {
+        case 'm':
+            move.move = true
+        case 'c':
+            move.capture = true
+        case 'i':
+            move.initial = true
+        case 'n':
+            move.lame = true
+        case 'e':
+            move.enPassant = true
+        case 'f', 'b', 'l', 'r', 's', 'v':
+            directionLetters += prefix[k:k+1]
+        default:
+            return nil, fmt.Errorf("invalid betza modifier %c", prefix[k])
+        }
+    }
+
+    if !move.move && !move.capture && !move.enPassant {
+        move.move = true
+        move.capture = true
+    }
+
+    directions, err := betzaDirections(betza_atoms[atom], directionLetters)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, direction := range directions {
+        move.directions[BETZA_U] = append(move.directions[BETZA_U], &Point{direction.x, direction.y})
+        move.directions[BETZA_R] = append(move.directions[BETZA_R], &Point{-direction.y, direction.x})
+        move.directions[BETZA_D] = append(move.directions[BETZA_D], &Point{-direction.x, -direction.y})
+        move.directions[BETZA_L] = append(move.directions[BETZA_L], &Point{direction.y, -direction.x})
+    }
+
+    return move, nil
+}
+
+// all rotations and reflections of the atom that match the direction letters
+func betzaDirections(atom Point, letters string) ([]Point, error) {
+    all := []Point{}
+    for _, candidate := range []Point{
+        {atom.x, atom.y}, {atom.y, atom.x}, {-atom.x, atom.y}, {-atom.y, atom.x},
+        {atom.x, -atom.y}, {atom.y, -atom.x}, {-atom.x, -atom.y}, {-atom.y, -atom.x},
+    } {
+        duplicate := false
+        for _, direction := range all {
+            duplicate = duplicate || direction == candidate
+        }
+
+        if !duplicate {
+            all = append(all, candidate)
+        }
+    }
+
+    if letters == "" {
+        return all, nil
+    }
+
+    orthogonal := atom.y == 0
+    oblique := atom.y != 0 && atom.x != atom.y
+
+    filters := []func(Point) bool{}
+    for i := 0; i < len(letters); i++ {
+        first := letters[i]
+        second := byte(0)
+        if i + 1 < len(letters) {
+            second = letters[i + 1]
+        }
+
+        pair := !orthogonal && strings.IndexByte("fb", first) >= 0 && strings.IndexByte("lr", second) >= 0
+        pair = pair || oblique && (first == second || strings.IndexByte("fb", first) >= 0 && second == 's' || strings.IndexByte("lr", first) >= 0 && second == 'v')
+        if pair {
+            filters = append(filters, betzaPairFilter(first, second))
+            i++
+        } else {
+            filters = append(filters, betzaFilter(first))
+        }
+    }
+
+    directions := []Point{}
+    for _, direction := range all {
+        for _, filter := range filters {
+            if filter(direction) {
+                directions = append(directions, direction)
+                break
+            }
+        }
+    }
+
+    if len(directions) == 0 {
+        return nil, fmt.Errorf("invalid betza directions %s", letters)
+    }
+
+    return directions, nil
+}
+
+func betzaFilter(letter byte) func(Point) bool {
+    switch letter {
+    case 'f':
+        return func(p Point) bool { return p.y < 0 }
+    case 'b':
+        return func(p Point) bool { return p.y > 0 }
+    case 'l':
+        return func(p Point) bool { return p.x < 0 }
+    case 'r':
+        return func(p Point) bool { return p.x > 0 }
+    case 's':
+        return func(p Point) bool { return p.x != 0 }
+    }
+
+    return func(p Point) bool { return p.y != 0 }
+}
+
+// fl, fr, bl, and br pick a quadrant, for oblique atoms ff, fs, lv, and so on pick the narrow or the wide half
+func betzaPairFilter(first byte, second byte) func(Point) bool {
+    narrow := func(p Point) bool { return abs(p.y) > abs(p.x) }
+    wide := func(p Point) bool { return abs(p.x) > abs(p.y) }
+
+    firstFilter := betzaFilter(first)
+    switch {
+    case second == 'l' || second == 'r':
+        secondFilter := betzaFilter(second)
+        return func(p Point) bool { return firstFilter(p) && secondFilter(p) }
+    case first == second && (first == 'f' || first == 'b'), second == 'v':
+        return func(p Point) bool { return firstFilter(p) && narrow(p) }
+    }
+
+    return func(p Point) bool { return firstFilter(p) && wide(p) }
+}
+
+func abs(n int) int {
+    if n < 0 {
+        return -n
+    }
+
+    return n
+}
+
+// pieces without an orientation face away from the side of their player
+func betzaOrientation(b *SimpleBoard, piece *Piece) int {
+    switch piece_types[piece.index].orientation {
+    case "U":
+        return BETZA_U
+    case "R":
+        return BETZA_R
+    case "D":
+        return BETZA_D
+    case "L":
+        return BETZA_L
+    }
+
+    if b.players == 4 {
+        return []int{BETZA_U, BETZA_R, BETZA_D, BETZA_L}[piece.color]
+    } else if piece.color == 0 {
+        return BETZA_U
+    }
+
+    return BETZA_D
+}
+
+func (p *betzaPiece) addMoves(b *SimpleBoard, fromPiece *Piece, fromLocation *Point) {
+    orientation := betzaOrientation(b, fromPiece)
+
+    for i := range p.moves {
+        move := &p.moves[i]
+        if move.initial && fromPiece.moved() {
+            continue
+        }
+
+        plain := move.move && move.capture && !move.lame && !move.enPassant && !fromPiece.isPawn()
+        for _, direction := range move.directions[orientation] {
+            if plain && move.limit == 0 {
+                addDirection(b, fromPiece, fromLocation, direction)
+            } else if plain && move.limit == 1 {
+                addSimple(b, fromPiece, fromLocation, direction)
+            } else {
+                addBetzaDirection(b, fromPiece, fromLocation, direction, move, orientation)
+            }
+        }
+    }
+}
+
+func addBetzaDirection(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, direction *Point, move *betzaMove, orientation int) {
+    currentLocation := fromLocation
+
+    for step := 1; move.limit == 0 || step <= move.limit; step++ {
+        if move.lame && !betzaLeapClear(b, currentLocation, direction) {
+            return
+        }
+
+        previousLocation := currentLocation
+        currentLocation = b.addIndex(currentLocation, direction)
+        if currentLocation == nil {
+            return
+        }
+
+        currentPiece := b.getPiece(currentLocation)
+        if currentPiece == nil { // no piece
+            if move.enPassant {
+                if r1, r2 := b.getEnPassantRisks(fromPiece.color, currentLocation); r1 != nil {
+                    addBetzaMove(b, fromPiece, fromLocation, currentLocation, orientation, func(newPiece *Piece) {
+                        addMoveCaptureEnPassant(b, fromPiece, fromLocation, currentPiece, currentLocation, newPiece, r1, r2)
+                    })
+                }
+            }
+
+            if move.move {
+                // a pawn leaping two squares straight ahead can be captured en passant on the square it passed
+                target := b.addIndex(previousLocation, &Point{sign(direction.x), sign(direction.y)})
+                if fromPiece.isPawn() && move.initial && abs(direction.x) + abs(direction.y) == 2 && direction.x * direction.y == 0 {
+                    addBetzaMove(b, fromPiece, fromLocation, currentLocation, orientation, func(newPiece *Piece) {
+                        addMoveRevealEnPassant(b, fromPiece, fromLocation, currentPiece, currentLocation, newPiece, target, currentLocation)
+                    })
+                } else {
+                    addBetzaMove(b, fromPiece, fromLocation, currentLocation, orientation, func(newPiece *Piece) {
+                        addMoveSimple(b, fromPiece, fromLocation, currentPiece, currentLocation, newPiece)
+                    })
+                }
+            }
+        } else if !b.allied(currentPiece.color, fromPiece.color) { // enemy piece
+            if move.capture {
+                addBetzaMove(b, fromPiece, fromLocation, currentLocation, orientation, func(newPiece *Piece) {
+                    addMoveSimple(b, fromPiece, fromLocation, currentPiece, currentLocation, newPiece)
+                })
+            }
+            return
+        } else { // ally piece
+            if move.capture {
+                addMoveAllyDefense(b, fromPiece, fromLocation, currentLocation)
+            }
+            return
+        }
+    }
+}
+
+// pawns promote once they can't move further forward
+func addBetzaMove(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, toLocation *Point, orientation int, add func(*Piece)) {
+    forward := betza_forwards[orientation]
+    if !fromPiece.isPawn() || b.addIndex(toLocation, forward) != nil {
+        add(nil)
+        return
+    }
+
+    for _, index := range b.promotions {
+        add(b.getAllPiece(fromPiece.color, index))
+    }
+}
+
+var betza_forwards = []*Point{
+    {0, -1},
+    {1, 0},
+    {0, 1},
+    {-1, 0},
+}
+
+// a lame leap moves orthogonally first, and can't jump over any square along its way
+func betzaLeapClear(b *SimpleBoard, fromLocation *Point, direction *Point) bool {
+    step := &Point{sign(direction.x), sign(direction.y)}
+    if abs(direction.x) != abs(direction.y) && direction.x != 0 && direction.y != 0 {
+        if abs(direction.x) > abs(direction.y) {
+            step = &Point{sign(direction.x), 0}
+        } else {
+            step = &Point{0, sign(direction.y)}
+        }
+
+        location := b.addIndex(fromLocation, step)
+        return location != nil && b.getPiece(location) == nil
+    }
+
+    location := fromLocation
+    for i := 1; i < max(abs(direction.x), abs(direction.y)); i++ {
+        location = b.addIndex(location, step)
+        if location == nil || b.getPiece(location) != nil {
+            return false
+        }
+    }
+
+    return true
+}
+
+// pieces with initial moves get a second type for after they moved, returns the index of the unmoved piece
+func registerBetzaPiece(t pieceType, notation string) (int, error) {
+    piece, err := parseBetza(notation)
+    if err != nil {
+        return -1, err
+    }
+
+    t.moves = piece.addMoves
+    if !piece.initial {
+        t.movedIndex = -1
+        return registerPiece(t)
+    }
+
+    moved := t
+    moved.movedIndex = -1
+    moved.moved = true
+
+    t.movedIndex = len(piece_types) + 1
+    return registerPiece(t, moved)
+}
+
+func mustRegisterBetzaPiece(t pieceType, notation string) int {
+    index, err := registerBetzaPiece(t, notation)
+    if err != nil {
+        panic(err)
+    }
+
+    return index
+}
@@ -0,0 +1,357 @@
+package chess
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+// registers a piece for one test, the registry is restored once the test ends
+func registerTestBetzaPiece(t *testing.T, pt pieceType, notation string) int {
+    count := totalPieces()
+    t.Cleanup(func() {
+        piece_types = piece_types[:count]
+    })
+
+    index, err := registerBetzaPiece(pt, notation)
+    assert.Nil(t, err)
+
+    return index
+}
+
+func Test_Betza_Directions(t *testing.T) {
+    directions, err := betzaDirections(betza_atoms['N'], "")
+    assert.Nil(t, err)
+    assert.Equal(t, 8, len(directions))
+
+    directions, err = betzaDirections(betza_atoms['W'], "fs")
+    assert.Nil(t, err)
+    assert.ElementsMatch(t, []Point{{0, -1}, {1, 0}, {-1, 0}}, directions)
+
+    directions, err = betzaDirections(betza_atoms['F'], "fl")
+    assert.Nil(t, err)
+    assert.ElementsMatch(t, []Point{{-1, -1}}, directions)
+
+    directions, err = betzaDirections(betza_atoms['N'], "ff")
+    assert.Nil(t, err)
+    assert.ElementsMatch(t, []Point{{1, -2}, {-1, -2}}, directions)
+
+    directions, err = betzaDirections(betza_atoms['N'], "fs")
+    assert.Nil(t, err)
+    assert.ElementsMatch(t, []Point{{2, -1}, {-2, -1}}, directions)
+
+    directions, err = betzaDirections(betza_atoms['N'], "frbl")
+    assert.Nil(t, err)
+    assert.ElementsMatch(t, []Point{{1, -2}, {2, -1}, {-1, 2}, {-2, 1}}, directions)
+}
+
+func Test_Betza_InvalidNotation(t *testing.T) {
+    for _, notation := range []string{"", "X", "fm", "xW", "W2f"} {
+        _, err := parseBetza(notation)
+        assert.NotNil(t, err, notation)
+    }
+}
+
+func Test_Betza_DuplicateName(t *testing.T) {
+    count := totalPieces()
+
+    _, err := registerBetzaPiece(pieceType{name: "A", value: 825}, "BN")
+    assert.NotNil(t, err)
+
+    registerTestBetzaPiece(t, pieceType{name: "BZ", value: 100, pawn: true}, "fmWifmnD")
+
+    // the moved version of the piece is taken as well
+    _, err = registerBetzaPiece(pieceType{name: "BZ", value: 100, moved: true}, "fW")
+    assert.NotNil(t, err)
+    assert.Equal(t, count + 2, totalPieces())
+}
+
+func Test_Betza_KnightWazir(t *testing.T) {
+    white := 0
+    black := 1
+
+    index := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 400}, "WN")
+    assert.Equal(t, index, piece_types[index].movedIndex)
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    piece := b.getAllPiece(white, index)
+    b.setPiece(b.getIndex(3, 3), piece)
+    b.setPiece(b.getIndex(3, 2), b.getAllPiece(black, KNIGHT))
+    b.setPiece(b.getIndex(4, 5), b.getAllPiece(white, KNIGHT))
+
+    piece.moves(b, b.getIndex(3, 3))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 10,
+        &b.captureMoves[white], 1,
+        &b.defenseMoves[white], 1,
+        []*Point{
+            b.getIndex(3, 2),
+            b.getIndex(3, 4),
+            b.getIndex(2, 3),
+            b.getIndex(4, 3),
+            b.getIndex(4, 5),
+            b.getIndex(2, 5),
+            b.getIndex(5, 4),
+            b.getIndex(1, 4),
+            b.getIndex(4, 1),
+            b.getIndex(2, 1),
+            b.getIndex(5, 2),
+            b.getIndex(1, 2),
+        },
+    )
+}
+
+func Test_Betza_Range(t *testing.T) {
+    white := 0
+    black := 1
+
+    index := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 300}, "fR2")
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    piece := b.getAllPiece(white, index)
+    b.setPiece(b.getIndex(3, 3), piece)
+    b.setPiece(b.getIndex(3, 2), b.getAllPiece(black, KNIGHT))
+
+    piece.moves(b, b.getIndex(3, 3))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 0,
+        &b.captureMoves[white], 1,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(3, 2),
+        },
+    )
+
+    b.setPiece(b.getIndex(3, 2), nil)
+    b.moves[white].clear()
+    b.captureMoves[white].clear()
+    piece.moves(b, b.getIndex(3, 3))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 2,
+        &b.captureMoves[white], 0,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(3, 2),
+            b.getIndex(3, 1),
+        },
+    )
+}
+
+func Test_Betza_Lame(t *testing.T) {
+    white := 0
+    black := 1
+
+    index := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 300}, "nN")
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    piece := b.getAllPiece(white, index)
+    b.setPiece(b.getIndex(3, 3), piece)
+    b.setPiece(b.getIndex(3, 2), b.getAllPiece(black, KNIGHT)) // blocks the two leaps forward
+    b.setPiece(b.getIndex(2, 3), b.getAllPiece(black, KNIGHT)) // blocks the two leaps to the left
+
+    piece.moves(b, b.getIndex(3, 3))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 4,
+        &b.captureMoves[white], 0,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(4, 5),
+            b.getIndex(2, 5),
+            b.getIndex(5, 4),
+            b.getIndex(5, 2),
+        },
+    )
+}
+
+func Test_Betza_Nightrider(t *testing.T) {
+    white := 0
+    black := 1
+
+    index := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 500}, "NN")
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    b.setPiece(b.getIndex(2, 4), b.getAllPiece(black, KNIGHT))
+
+    b.setPiece(b.getIndex(0, 0), b.getAllPiece(white, NIGHTRIDER))
+    b.getPiece(b.getIndex(0, 0)).moves(b, b.getIndex(0, 0))
+    expected := b.moves[white].count + b.captureMoves[white].count
+    b.moves[white].clear()
+    b.captureMoves[white].clear()
+
+    b.setPiece(b.getIndex(0, 0), b.getAllPiece(white, index))
+    b.getPiece(b.getIndex(0, 0)).moves(b, b.getIndex(0, 0))
+    assert.Equal(t, expected, b.moves[white].count + b.captureMoves[white].count)
+    assert.Equal(t, 1, b.captureMoves[white].count)
+}
+
+func Test_Betza_Pawn(t *testing.T) {
+    white := 0
+    black := 1
+
+    index := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 100, pawn: true}, "fmWfceFifmnD")
+    movedIndex := piece_types[index].movedIndex
+    assert.Equal(t, index + 1, movedIndex)
+    assert.True(t, piece_types[movedIndex].moved)
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    pawn := b.getAllPiece(white, index)
+    b.setPiece(b.getIndex(3, 6), pawn)
+    b.setPiece(b.getIndex(2, 5), b.getAllPiece(black, KNIGHT))
+    b.setPiece(b.getIndex(4, 5), b.getAllPiece(white, KNIGHT))
+
+    pawn.moves(b, b.getIndex(3, 6))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 2,
+        &b.captureMoves[white], 1,
+        &b.defenseMoves[white], 1,
+        []*Point{
+            b.getIndex(3, 5),
+            b.getIndex(3, 4),
+            b.getIndex(2, 5),
+            b.getIndex(4, 5),
+        },
+    )
+
+    // the double step can be captured en passant on the square it passed
+    for i := 0; i < b.moves[white].count; i++ {
+        move := b.moves[white].array[i]
+        if move.toLocation == b.getIndex(3, 4) {
+            assert.Equal(t, b.getIndex(3, 5), move.newTarget)
+            assert.Equal(t, b.getIndex(3, 4), move.newRisk)
+        }
+    }
+
+    // the double step is blocked and the moved pawn doesn't have it
+    b.moves[white].clear()
+    b.captureMoves[white].clear()
+    b.defenseMoves[white].clear()
+    b.setPiece(b.getIndex(3, 5), b.getAllPiece(black, KNIGHT))
+    pawn.moves(b, b.getIndex(3, 6))
+    assert.Equal(t, 0, b.moves[white].count)
+
+    b.moves[white].clear()
+    b.captureMoves[white].clear()
+    b.defenseMoves[white].clear()
+    b.setPiece(b.getIndex(3, 5), nil)
+    b.setPiece(b.getIndex(3, 6), b.getAllPiece(white, movedIndex))
+    b.getPiece(b.getIndex(3, 6)).moves(b, b.getIndex(3, 6))
+    assert.Equal(t, 1, b.moves[white].count)
+}
+
+func Test_Betza_PawnEnPassantAndPromotion(t *testing.T) {
+    white := 0
+    black := 1
+
+    index := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 100, pawn: true}, "fmWfceF")
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    pawn := b.getAllPiece(white, index)
+    b.setPiece(b.getIndex(3, 3), pawn)
+    b.setPiece(b.getIndex(4, 3), b.getAllPiece(black, PAWN_D_M))
+    b.setEnPassant(black, b.getIndex(4, 2), b.getIndex(4, 3))
+
+    pawn.moves(b, b.getIndex(3, 3))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 1,
+        &b.captureMoves[white], 1,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(3, 2),
+            b.getIndex(4, 2),
+        },
+    )
+
+    b.moves[white].clear()
+    b.captureMoves[white].clear()
+    b.setPiece(b.getIndex(3, 3), nil)
+    b.setPiece(b.getIndex(3, 1), pawn)
+    pawn.moves(b, b.getIndex(3, 1))
+
+    assert.Equal(t, 4, b.moves[white].count)
+
+    b.moves[white].clear()
+    b.promotions = []int{ARCHBISHOP, CHANCELLOR}
+    pawn.moves(b, b.getIndex(3, 1))
+
+    assert.Equal(t, 2, b.moves[white].count)
+    assert.Equal(t, ARCHBISHOP, b.moves[white].array[0].promotionIndex)
+}
+
+func Test_Betza_FourPlayerOrientation(t *testing.T) {
+    red := 1
+    yellow := 2
+
+    index := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 100}, "fW")
+    orientedIndex := registerTestBetzaPiece(t, pieceType{name: "BZ", value: 100, orientation: "L"}, "fW")
+
+    b, err := newSimpleBoard(14, 14, 4)
+    assert.Nil(t, err)
+
+    piece := b.getAllPiece(red, index)
+    b.setPiece(b.getIndex(5, 5), piece)
+    piece.moves(b, b.getIndex(5, 5))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[red], 1,
+        &b.captureMoves[red], 0,
+        &b.defenseMoves[red], 0,
+        []*Point{
+            b.getIndex(6, 5),
+        },
+    )
+
+    piece = b.getAllPiece(yellow, index)
+    b.setPiece(b.getIndex(8, 8), piece)
+    piece.moves(b, b.getIndex(8, 8))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[yellow], 1,
+        &b.captureMoves[yellow], 0,
+        &b.defenseMoves[yellow], 0,
+        []*Point{
+            b.getIndex(8, 9),
+        },
+    )
+
+    // an oriented piece ignores the side of its player
+    piece = b.getAllPiece(red, orientedIndex)
+    b.moves[red].clear()
+    b.setPiece(b.getIndex(5, 5), piece)
+    piece.moves(b, b.getIndex(5, 5))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[red], 1,
+        &b.captureMoves[red], 0,
+        &b.defenseMoves[red], 0,
+        []*Point{
+            b.getIndex(4, 5),
+        },
+    )
+}
@@ -33,6 +33,9 @@ func newSimpleBoard(x int, y int, players int) (*SimpleBoard, error) {
     captureMoves := make([]Array1000[FastMove], players)
     defenseMoves := make([]Array1000[FastMove], players)
     allPieces := make([][]Piece, players)
+    reserves := make([][]int, players)
+    dropPawns := make([]int, players)
+    drops := make([][]FastMove, players)
     for i := 0; i < players; i++ {
         playersDisabled[i] = false
         enPassantTargets[i] = nil
@@ -44,28 +47,12 @@ func newSimpleBoard(x int, y int, players int) (*SimpleBoard, error) {
         moves[i] = Array1000[FastMove]{}
         captureMoves[i] = Array1000[FastMove]{}
         defenseMoves[i] = Array1000[FastMove]{}
-        allPieces[i] = []Piece{
-            {i, PAWN_R},
-            {i, PAWN_L},
-            {i, PAWN_D},
-            {i, PAWN_U},
-            {i, PAWN_R_M},
-            {i, PAWN_L_M},
-            {i, PAWN_D_M},
-            {i, PAWN_U_M},
-            {i, KNIGHT},
-            {i, BISHOP},
-            {i, ROOK},
-            {i, ROOK_M},
-            {i, QUEEN},
-            {i, KING_R},
-            {i, KING_L},
-            {i, KING_D},
-            {i, KING_U},
-            {i, KING_R_M},
-            {i, KING_L_M},
-            {i, KING_D_M},
-            {i, KING_U_M},
+        allPieces[i] = make([]Piece, totalPieces())
+        reserves[i] = make([]int, totalPieces())
+        dropPawns[i] = -1
+        drops[i] = []FastMove{}
+        for index := range allPieces[i] {
+            allPieces[i][index] = Piece{i, index}
         }
     }
 
@@ -88,8 +75,8 @@ func newSimpleBoard(x int, y int, players int) (*SimpleBoard, error) {
     zobristEnPassant := make([][][]uint64, players)
     zobristVulnerable := make([][][]uint64, players)
     for i := 0; i < players; i++ {
-        zobristPieces[i] = make([][][]uint64, TOTAL_PIECES)
-        for j := 0; j < TOTAL_PIECES; j++ {
+        zobristPieces[i] = make([][][]uint64, totalPieces())
+        for j := 0; j < totalPieces(); j++ {
             zobristPieces[i][j] = make([][]uint64, y)
             for yi := 0; yi < y; yi++ {
                 zobristPieces[i][j][yi] = make([]uint64, x)
@@ -110,8 +97,8 @@ func newSimpleBoard(x int, y int, players int) (*SimpleBoard, error) {
         }
     }
 
-    pieceSquareTables := make([][][]int, TOTAL_PIECES)
-    for i := 0; i < TOTAL_PIECES; i++ {
+    pieceSquareTables := make([][][]int, totalPieces())
+    for i := 0; i < totalPieces(); i++ {
         pieceSquareTables[i] = make([][]int, y)
         for yi := 0; yi < y; yi++ {
             pieceSquareTables[i][yi] = make([]int, x)
@@ -126,6 +113,7 @@ func newSimpleBoard(x int, y int, players int) (*SimpleBoard, error) {
         y: y,
         players: players,
         test: test,
+        promotions: default_promotions,
 
         playersDisabled: playersDisabled,
         enPassantTargets: enPassantTargets,
@@ -138,6 +126,9 @@ func newSimpleBoard(x int, y int, players int) (*SimpleBoard, error) {
         captureMoves: captureMoves,
         defenseMoves: defenseMoves,
         allPieces: allPieces,
+        reserves: reserves,
+        dropPawns: dropPawns,
+        drops: drops,
 
         disableds: disableds,
         indexes: indexes,
@@ -156,6 +147,13 @@ type SimpleBoard struct {
     y int
     players int
     test bool
+    teams []int // team of each color, nil when every color plays for itself
+    crazyhouse bool // captured pieces go into the capturer's reserve and can be dropped
+    promoted [][]bool // [y][x] pieces that promoted from a pawn, nil without crazyhouse
+    bughouse bool // captured pieces leave the board for the reserve of the partner on a linked board
+    atomic bool // captures explode the capturer and the pieces next to the captured square, kings can't capture
+    hill []*Point // squares where a king wins, nil without king of the hill
+    promotions []int // pieces a pawn promotes to, in the order their moves are generated
 
     // arrays of size PLAYERS
     playersDisabled []bool
@@ -169,6 +167,9 @@ type SimpleBoard struct {
     captureMoves []Array1000[FastMove]
     defenseMoves []Array1000[FastMove]
     allPieces [][]Piece
+    reserves [][]int // [player][piece] pieces in hand
+    dropPawns []int // [player] pawn dropped by the player, -1 when they can't drop pawns
+    drops [][]FastMove // [player] drop moves, not in a fixed size array since every empty square can take a drop
 
     // arrays of size X * Y
     disableds [][]bool
@@ -182,6 +183,17 @@ type SimpleBoard struct {
     zobristPieces [][][][]uint64 // [player][piece][y][x]
     zobristEnPassant [][][]uint64 // [player][y][x] (x and y of target)
     zobristVulnerable [][][]uint64 // [player][y][x] (x and y of start)
+    zobristReserves [][][]uint64 // [player][piece][count] (only with crazyhouse)
+}
+
+// the teams have to contain every color
+func (b *SimpleBoard) setTeams(teams []int) {
+    b.teams = append([]int{}, teams...)
+}
+
+// teammates don't capture each other's pieces, they defend them
+func (b *SimpleBoard) allied(color1 int, color2 int) bool {
+    return color1 == color2 || (b.teams != nil && b.teams[color1] == b.teams[color2])
 }
 
 func (b *SimpleBoard) disablePieces(color int, disable bool) {
@@ -259,7 +271,7 @@ func (b *SimpleBoard) getEnPassantRisks(color int, target *Point) (*Point, *Poin
     var risk2 *Point
 
     for i := 0; i < b.players; i++ {
-        if i == color {
+        if b.allied(i, color) {
             continue
         }
 
@@ -299,6 +311,8 @@ func (b *SimpleBoard) MovesOfColor(color int, moves *[]FastMove) {
 
         *moves = append(*moves, move)
     }
+
+    *moves = append(*moves, b.drops[color]...)
 }
 
 func (b *SimpleBoard) MovesOfLocation(fromLocation *Point, moves *[]FastMove) {
@@ -385,13 +399,13 @@ func (b *SimpleBoard) LegalMovesOfLocation(fromLocation *Point) ([]FastMove, err
     return legalMoves, nil
 }
 
-// TODO add 3 move repetition and 50 move rule
 func (b *SimpleBoard) CalculateMoves() {
     for i := 0; i < b.players; i++ {
         b.moves[i].clear()
         b.captureMoves[i].clear()
         b.defenseMoves[i].clear()
         b.queenMoveCount[i] = 0
+        b.kingLocations[i] = nil // pieces of eliminated players can lose their king
     }
 
     for y := 0; y < b.y; y++ {
@@ -421,15 +435,40 @@ func (b *SimpleBoard) CalculateMoves() {
             }
         }
     }
+
+    for color := 0; color < b.players; color++ {
+        location := b.kingLocations[color]
+        king := b.getPiece(location)
+        if b.playersDisabled[color] || king == nil || king.color != color || !king.isKing() {
+            continue
+        }
+
+        addCastles(b, king, location)
+    }
+
+    if !b.crazyhouse {
+        return
+    }
+
+    for color := 0; color < b.players; color++ {
+        b.drops[color] = b.drops[color][:0]
+        if !b.playersDisabled[color] {
+            b.addDrops(color)
+        }
+    }
 }
 
 func (b *SimpleBoard) Check(color int) bool {
+    if b.atomic {
+        return b.atomicCheck(color)
+    }
+
     king := b.kingLocations[color]
     start := b.vulnerableStarts[color]
     end := b.vulnerableEnds[color]
 
     for i := 0; i < b.players; i++ {
-        if i == color {
+        if b.allied(i, color) {
             continue
         }
         captureMoves := &b.captureMoves[i]
@@ -449,6 +488,43 @@ func (b *SimpleBoard) Check(color int) bool {
     return false
 }
 
+// pawns only capture on occupied squares, so their attacks on empty squares are found from the board
+func (b *SimpleBoard) attacked(location *Point, color int) bool {
+    for i := 0; i < b.players; i++ {
+        if b.allied(i, color) {
+            continue
+        }
+
+        for j := 0; j < b.captureMoves[i].count; j++ {
+            if b.captureMoves[i].array[j].toLocation == location {
+                return true
+            }
+        }
+
+        for j := 0; j < b.moves[i].count; j++ {
+            move := &b.moves[i].array[j]
+            if move.toLocation == location && !b.getPiece(move.fromLocation).isPawn() {
+                return true
+            }
+        }
+    }
+
+    for _, direction := range bishop_directions {
+        from := b.addIndex(location, direction)
+        piece := b.getPiece(from)
+        if piece == nil || b.allied(piece.color, color) || !piece.isPawn() || b.playersDisabled[piece.color] {
+            continue
+        }
+
+        directions := pawnDirections(piece.index)
+        if b.addIndex(from, directions[3]) == location || b.addIndex(from, directions[4]) == location {
+            return true
+        }
+    }
+
+    return false
+}
+
 func (b *SimpleBoard) CheckmateAndStalemate(color int) (bool, bool, error) {
     legalMoves, err := b.LegalMovesOfColor(color)
     if err != nil {
@@ -466,6 +542,74 @@ func (b *SimpleBoard) CheckmateAndStalemate(color int) (bool, bool, error) {
     return false, true, nil
 }
 
+// a conservative dead position check, pieces of disabled players don't move but still block squares
+// with only kings no checkmate is possible on any board
+// minor pieces are only allowed for two players on a rectangular board without disabled squares or pieces
+func (b *SimpleBoard) InsufficientMaterial() bool {
+    // pieces in hand can still be dropped, and a lone king can still walk to the hill
+    if b.hasReserves() || b.hill != nil {
+        return false
+    }
+
+    colors := 0 // bit per color
+    colorCount := 0
+    blocked := false
+    minors := 0
+    knights := 0
+    bishopSquares := [2]bool{} // light and dark
+
+    for y := 0; y < b.y; y++ {
+        for x := 0; x < b.x; x++ {
+            if b.disableds[y][x] {
+                blocked = true
+                continue
+            }
+
+            piece := b.pieces[y][x]
+            if piece == nil {
+                continue
+            }
+
+            if b.playersDisabled[piece.color] {
+                blocked = true
+                continue
+            }
+
+            if colors & (1 << piece.color) == 0 {
+                colors |= 1 << piece.color
+                colorCount++
+            }
+
+            switch {
+            case piece.isKing():
+            case piece.index == KNIGHT:
+                minors++
+                knights++
+            case piece.index == BISHOP:
+                minors++
+                bishopSquares[(x + y) % 2] = true
+            default:
+                return false
+            }
+        }
+    }
+
+    if minors == 0 {
+        return true
+    }
+
+    if blocked || colorCount != 2 {
+        return false
+    }
+
+    // two minor pieces can explode a king next to an enemy piece
+    if b.atomic {
+        return minors == 1
+    }
+
+    return minors == 1 || (knights == 0 && bishopSquares[0] != bishopSquares[1])
+}
+
 func (b *SimpleBoard) Print() string {
 	var builder strings.Builder
 	var cellWidth int = 12
@@ -535,11 +679,17 @@ func (b *SimpleBoard) State() *BoardData {
         }
     }
 
+    reserves := []map[string]int{}
+    if b.crazyhouse {
+        reserves = b.reservesState()
+    }
+
     return &BoardData{
         XSize: b.x,
         YSize: b.y,
         Disabled: disabled,
         Pieces: pieces,
+        Reserves: reserves,
     }
 }
 
@@ -549,8 +699,35 @@ func (b *SimpleBoard) Copy() (*SimpleBoard, error) {
         return nil, err
     }
 
+    if b.teams != nil {
+        simpleBoard.setTeams(b.teams)
+    }
+
+    simpleBoard.crazyhouse = b.crazyhouse
+    if b.zobristReserves != nil {
+        simpleBoard.setZobristReserves()
+    }
+    if b.promoted != nil {
+        simpleBoard.promoted = make([][]bool, b.y)
+        for y := range b.promoted {
+            simpleBoard.promoted[y] = append([]bool{}, b.promoted[y]...)
+        }
+    }
+    simpleBoard.bughouse = b.bughouse
+    simpleBoard.atomic = b.atomic
+    simpleBoard.promotions = b.promotions
+
+    if b.hill != nil {
+        simpleBoard.hill = []*Point{}
+        for _, location := range b.hill {
+            simpleBoard.hill = append(simpleBoard.hill, simpleBoard.getIndex(location.x, location.y))
+        }
+    }
+
     for i := 0; i < b.players; i++ {
         simpleBoard.playersDisabled[i] = b.playersDisabled[i]
+        simpleBoard.dropPawns[i] = b.dropPawns[i]
+        copy(simpleBoard.reserves[i], b.reserves[i])
 
         enPassantTarget := b.enPassantTargets[i]
         if enPassantTarget == nil {
@@ -616,6 +793,16 @@ func (b *SimpleBoard) ZobristHash() uint64 {
         if vulnerableStart != nil {
             hash ^= b.zobristVulnerable[color][vulnerableStart.y][vulnerableStart.x]
         }
+
+        if !b.crazyhouse {
+            continue
+        }
+
+        for index, count := range b.reserves[color] {
+            if count > 0 {
+                hash ^= b.zobristReserves[color][index][count]
+            }
+        }
     }
 
     return hash
@@ -112,6 +112,47 @@ func Test_CalculateMoves_stalemate(t *testing.T) {
     Assert_CountsAndMatest(t, b, white, 0, false, true, black, 26, false, false)
 }
 
+func Test_InsufficientMaterial(t *testing.T) {
+    for fen, expected := range map[string]bool{
+        "4k3/8/8/8/8/8/8/4K3 w - - 0 1": true,
+        "4k3/8/8/8/8/8/8/4KN2 w - - 0 1": true,
+        "4k3/8/8/8/8/8/8/2B1K3 w - - 0 1": true,
+        "4kb2/8/8/8/8/8/8/2B1K3 w - - 0 1": true, // same colored bishops
+        "4k1b1/8/8/8/8/8/8/2B1K3 w - - 0 1": false,
+        "4kn2/8/8/8/8/8/8/4KN2 w - - 0 1": false,
+        "4k3/8/8/8/8/8/8/3NKN2 w - - 0 1": false,
+        "4k3/8/8/8/8/8/8/4K2R w - - 0 1": false,
+        "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1": false,
+    } {
+        b, _, _, err := createSimpleBoardFromFEN(fen)
+        assert.Nil(t, err)
+        assert.Equal(t, expected, b.InsufficientMaterial(), fen)
+    }
+}
+
+func Test_InsufficientMaterial_FourPlayer(t *testing.T) {
+    red := 0
+    blue := 1
+    yellow := 2
+
+    b, err := newSimpleBoard(8, 8, 4)
+    assert.Nil(t, err)
+
+    b.setPiece(b.getIndex(0, 7), b.getAllPiece(red, KING_U))
+    b.setPiece(b.getIndex(0, 0), b.getAllPiece(blue, KING_R))
+    b.setPiece(b.getIndex(7, 0), b.getAllPiece(yellow, KING_D))
+    b.setPiece(b.getIndex(4, 4), b.getAllPiece(yellow, QUEEN))
+    assert.False(t, b.InsufficientMaterial())
+
+    // eliminated pieces stay on the board but can't give checkmate
+    b.disablePieces(yellow, true)
+    assert.True(t, b.InsufficientMaterial())
+
+    // they can block squares, so a minor piece might be enough
+    b.setPiece(b.getIndex(2, 2), b.getAllPiece(red, KNIGHT))
+    assert.False(t, b.InsufficientMaterial())
+}
+
 func Test_CalculateMoves_noCastleThroughCheck(t *testing.T) {
     white := 0
     black := 1
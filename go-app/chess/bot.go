@@ -3,6 +3,7 @@ package chess
 import (
     "time"
     "fmt"
+    "sync/atomic"
 )
 
 /*
@@ -11,14 +12,23 @@ Responsible for:
 */
 type Bot interface {
     FindMoveIterativeDeepening() (MoveKey, error)
+    Nodes() int64 // positions visited by the last search
 }
 
 func NewSimpleBot(game Game, depthLimit int, timeLimitSeconds int) (Bot, error) {
+    return newSimpleBotWithTimeLimit(game, depthLimit, time.Duration(timeLimitSeconds) * time.Second)
+}
+
+func NewSimpleBotWithTimeLimit(game Game, depthLimit int, timeLimit time.Duration) (Bot, error) {
+    return newSimpleBotWithTimeLimit(game, depthLimit, timeLimit)
+}
+
+func newSimpleBotWithTimeLimit(game Game, depthLimit int, timeLimit time.Duration) (*SimpleBot, error) {
     return &SimpleBot{
         game: game,
-        depthStart: 2,
+        depthStart: min(2, depthLimit),
         depthLimit: depthLimit,
-        timeLimitSeconds: 5,
+        timeLimit: timeLimit,
     }, nil
 }
 
@@ -26,16 +36,23 @@ type SimpleBot struct {
     game Game
     depthStart int
     depthLimit int
-    timeLimitSeconds int
+    timeLimit time.Duration
+    nodes atomic.Int64
+}
+
+func (b *SimpleBot) Nodes() int64 {
+    return b.nodes.Load()
 }
 
 func (b *SimpleBot) FindMoveIterativeDeepening() (MoveKey, error) {
-    result := make(chan *MoveKey)
-    stop := make(chan bool)
+    // buffered so a search finishing at the time limit doesn't block either side
+    result := make(chan *MoveKey, 1)
+    stop := make(chan bool, 1)
 
     moveKey := MoveKey{}
     err := fmt.Errorf("No move found")
-    endTime := time.Now().Add(time.Duration(b.timeLimitSeconds) * time.Second)
+    endTime := time.Now().Add(b.timeLimit)
+    b.nodes.Store(0)
 
     for depth := b.depthStart; depth <= b.depthLimit; depth++ {
         go b.findMove(depth, result, stop)
@@ -70,6 +87,7 @@ func (b *SimpleBot) findMove(depth int, result chan *MoveKey, stop chan bool) {
     }
 
     searcher := newParallelSearcher(boardCopy, playerCollectionCopy, stop)
+    searcher.nodes = &b.nodes
 
     moveKey, err := searcher.searchWithMinimax(depth)
     if err != nil {
@@ -83,6 +101,7 @@ func (b *SimpleBot) findMove(depth int, result chan *MoveKey, stop chan bool) {
         XFrom: moveKey.XFrom,
         YFrom: moveKey.YFrom,
         Promotion: moveKey.Promotion,
+        Drop: moveKey.Drop,
     }
 }
 
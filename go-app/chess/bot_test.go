@@ -15,13 +15,13 @@ func Test_Bot(t *testing.T) {
 
     moveKey, err := bot.FindMoveIterativeDeepening()
     assert.Nil(t, err)
-    assert.Equal(t, MoveKey{3, 3, 3, 2, ""}, moveKey)
+    assert.Equal(t, MoveKey{XFrom: 3, YFrom: 3, XTo: 3, YTo: 2}, moveKey)
 
     bot, err = NewSimpleBot(game, 5, 5)
     assert.Nil(t, err)
 
     moveKey, err = bot.FindMoveIterativeDeepening()
     assert.Nil(t, err)
-    assert.Equal(t, MoveKey{2, 3, 2, 2, ""}, moveKey)
+    assert.Equal(t, MoveKey{XFrom: 2, YFrom: 3, XTo: 2, YTo: 2}, moveKey)
 }
 
@@ -0,0 +1,103 @@
+package chess
+
+import "fmt"
+
+/*
+Responsible for:
+- handing the pieces captured on a bughouse board to the linked board
+- ending the board when the linked board ends
+*/
+
+// a game that can be linked to a partner bughouse board
+type BughouseGame interface {
+    Game
+    Captures() []*CaptureData // pieces captured by the last move that go to the linked bughouse board
+    Deposit(color int, piece string) error // puts a piece captured on the linked bughouse board into the reserve of the color
+    End(winner int) error // ends the game together with the linked bughouse board, -1 for a draw
+}
+
+// the captured pieces keep their color, the partner on the linked board plays that color
+func bughouseCaptures(b *SimpleBoard, move *FastMove) []*CaptureData {
+    captures := []*CaptureData{}
+    if !b.bughouse || move.drop != nil {
+        return captures
+    }
+
+    // called before the move is executed, so the board still knows which pieces promoted
+    for i := 1; i < move.oldPiece.count; i++ {
+        piece := move.oldPiece.at(i)
+        if piece == nil || piece.isKing() || b.allied(piece.color, move.color) {
+            continue
+        }
+
+        location := move.location.at(i)
+        piece = b.capturedPiece(piece, b.promoted[location.y][location.x])
+
+        captures = append(captures, &CaptureData{
+            T: piece.print(),
+            C: piece.color,
+        })
+    }
+
+    return captures
+}
+
+func (s *SimpleGame) Captures() []*CaptureData {
+    return s.captures
+}
+
+func (s *SimpleGame) Deposit(color int, piece string) error {
+    if !s.b.bughouse {
+        return fmt.Errorf("not a bughouse game")
+    }
+
+    if s.p.getGameOver() {
+        return fmt.Errorf("game is over")
+    }
+
+    if s.p.colorOutOfBounds(color) {
+        return fmt.Errorf("invalid player")
+    }
+
+    index := pieceIndexFromName(piece)
+    if index < 0 {
+        return fmt.Errorf("invalid piece %s", piece)
+    }
+
+    reserve := s.b.getAllPiece(color, index)
+    index = s.b.reserveIndex(reserve, color)
+    if index < 0 {
+        return fmt.Errorf("invalid piece %s", piece)
+    }
+
+    s.recordStartPosition()
+
+    transition := PlayerTransition{}
+    createDepositTransition(s.b, s.p, s.b.getAllPiece(color, index), &transition)
+
+    err := s.i.executeHalf(transition)
+    if err != nil {
+        return err
+    }
+
+    s.b.CalculateMoves()
+
+    return nil
+}
+
+func (s *SimpleGame) End(winner int) error {
+    if s.p.getGameOver() {
+        return fmt.Errorf("game is over")
+    }
+
+    if winner >= 0 && s.p.colorOutOfBounds(winner) {
+        return fmt.Errorf("invalid player")
+    }
+
+    s.recordStartPosition()
+
+    transition := PlayerTransition{}
+    createEndTransition(s.b, s.p, winner, &transition)
+
+    return s.i.executeHalf(transition)
+}
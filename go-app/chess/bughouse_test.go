@@ -0,0 +1,81 @@
+package chess
+
+import (
+    "os"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func newBughouseGame(t *testing.T, data string) BughouseGame {
+    game, ok := newVariantGame(t, data).(BughouseGame)
+    assert.True(t, ok)
+
+    return game
+}
+
+func Test_Bughouse_CapturesAndDeposits(t *testing.T) {
+    white := 0
+    black := 1
+
+    data, err := os.ReadFile("../variants/bughouse.json")
+    assert.Nil(t, err)
+    game := newBughouseGame(t, string(data))
+    partner := newBughouseGame(t, string(data))
+
+    for _, san := range []string{"e4", "d5", "exd5"} {
+        err = game.ExecuteSAN(san)
+        assert.Nil(t, err)
+    }
+
+    // the captured pawn leaves the board instead of going into white's reserve
+    assert.Equal(t, []*CaptureData{{T: "P", C: black}}, game.Captures())
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []map[string]int{{}, {}}, state.Reserves)
+
+    err = game.ExecuteSAN("Qxd5")
+    assert.Nil(t, err)
+    assert.Equal(t, []*CaptureData{{T: "P", C: white}}, game.Captures())
+
+    err = partner.ExecuteSAN("e4")
+    assert.Nil(t, err)
+    err = partner.Deposit(black, "P")
+    assert.Nil(t, err)
+    err = partner.Deposit(black, "K")
+    assert.NotNil(t, err)
+
+    state, err = partner.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []map[string]int{{}, {"P": 1}}, state.Reserves)
+    assert.Equal(t, black, state.CurrentPlayer) // depositing doesn't change the turn
+
+    err = partner.ExecuteSAN("P@d5")
+    assert.Nil(t, err)
+    assert.Equal(t, []*CaptureData{}, partner.Captures())
+
+    // a linked board ending ends this board with the winner's partner
+    err = partner.End(black)
+    assert.Nil(t, err)
+    state, err = partner.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, black, state.WinningPlayer)
+
+    err = partner.Deposit(white, "P")
+    assert.NotNil(t, err)
+    err = partner.End(white)
+    assert.NotNil(t, err)
+}
+
+func Test_Bughouse_Validate(t *testing.T) {
+    definition, err := ParseVariant([]byte(`{"Width": 8, "Height": 8, "Players": 4, "Bughouse": true}`))
+    assert.Nil(t, err)
+    assert.NotNil(t, definition.Validate())
+
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+    bughouseGame, ok := game.(BughouseGame)
+    assert.True(t, ok)
+    assert.NotNil(t, bughouseGame.Deposit(0, "P")) // only bughouse boards take pieces from a linked board
+}
@@ -0,0 +1,166 @@
+package chess
+
+import (
+    "fmt"
+    "math/rand"
+)
+
+/*
+Responsible for:
+- numbering the chess960 starting positions
+- shuffling the back ranks of a board into a chess960 starting position
+*/
+const CHESS960_POSITIONS = 960
+const CHESS960_STANDARD = 518 // RNBQKBNR
+
+// the two knights on the five squares left after the bishops and the queen are placed
+var chess960_knights = [][2]int{
+    {0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4},
+}
+
+// a negative number picks a random position
+func NewChess960Game(number int) (Game, error) {
+    b, err := createSimpleBoardWithDefaultPieceLocations()
+    if err != nil {
+        return nil, err
+    }
+
+    p, err := createSimplePlayerCollectionWithDefaultPlayers()
+    if err != nil {
+        return nil, err
+    }
+
+    return newChess960Game(b, p, number)
+}
+
+// every player gets the same back rank, read from their left to their right as seen from the bottom player
+func NewChess960FourPlayerGame(number int) (Game, error) {
+    b, err := createSimpleFourPlayerBoardWithDefaultPieceLocations()
+    if err != nil {
+        return nil, err
+    }
+
+    p, err := createSimpleFourPlayerPlayerCollectionWithDefaultPlayers()
+    if err != nil {
+        return nil, err
+    }
+
+    return newChess960Game(b, p, number)
+}
+
+func newChess960Game(b *SimpleBoard, p *SimplePlayerCollection, number int) (Game, error) {
+    err := shuffleChess960BackRanks(b, number)
+    if err != nil {
+        return nil, err
+    }
+
+    b.CalculateMoves()
+
+    i, err := invokerFactoryInstance.newSimpleInvoker()
+    if err != nil {
+        return nil, err
+    }
+
+    return &SimpleGame{
+        b: b,
+        p: p,
+        i: i,
+    }, nil
+}
+
+// scharnagl numbering, the bishops, the queen, and the knights are placed in that order and the rest is RKR
+func Chess960BackRank(number int) (string, error) {
+    if number < 0 || number >= CHESS960_POSITIONS {
+        return "", fmt.Errorf("invalid chess960 position %d", number)
+    }
+
+    backRank := make([]byte, 8)
+
+    backRank[number % 4 * 2 + 1] = 'B'
+    number /= 4
+    backRank[number % 4 * 2] = 'B'
+    number /= 4
+
+    placeChess960Piece(backRank, 'Q', number % 6)
+    number /= 6
+
+    knights := chess960_knights[number]
+    placeChess960Piece(backRank, 'N', knights[1])
+    placeChess960Piece(backRank, 'N', knights[0])
+
+    placeChess960Piece(backRank, 'R', 0)
+    placeChess960Piece(backRank, 'K', 0)
+    placeChess960Piece(backRank, 'R', 0)
+
+    return string(backRank), nil
+}
+
+// puts the piece on the nth empty square
+func placeChess960Piece(backRank []byte, letter byte, n int) {
+    for i := range backRank {
+        if backRank[i] != 0 {
+            continue
+        }
+
+        if n == 0 {
+            backRank[i] = letter
+            return
+        }
+        n--
+    }
+}
+
+// replaces the pieces on the line of every king, the line must hold exactly the eight standard pieces
+func shuffleChess960BackRanks(b *SimpleBoard, number int) error {
+    if number < 0 {
+        number = rand.Intn(CHESS960_POSITIONS)
+    }
+
+    backRank, err := Chess960BackRank(number)
+    if err != nil {
+        return err
+    }
+
+    for color := 0; color < b.players; color++ {
+        kingLocation := findKing(b, color)
+        if kingLocation == nil {
+            return fmt.Errorf("chess960 requires a king for color %d", color)
+        }
+        king := b.getPiece(kingLocation)
+
+        locations := []*Point{}
+        for i := 0; i < max(b.x, b.y); i++ {
+            location := b.getIndex(i, kingLocation.y)
+            if !isKingUD(king.index) {
+                location = b.getIndex(kingLocation.x, i)
+            }
+
+            piece := b.getPiece(location)
+            if piece != nil && piece.color == color && !piece.isPawn() {
+                locations = append(locations, location)
+            }
+        }
+
+        if len(locations) != len(backRank) {
+            return fmt.Errorf("chess960 requires %d back rank pieces for color %d", len(backRank), color)
+        }
+
+        for i, location := range locations {
+            index := unmovedKingIndex(king.index)
+            switch backRank[i] {
+            case 'R':
+                index = ROOK
+            case 'N':
+                index = KNIGHT
+            case 'B':
+                index = BISHOP
+            case 'Q':
+                index = QUEEN
+            }
+
+            b.setPiece(location, b.getAllPiece(color, index))
+        }
+    }
+
+    return nil
+}
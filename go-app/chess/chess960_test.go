@@ -0,0 +1,295 @@
+package chess
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_Chess960BackRank(t *testing.T) {
+    backRank, err := Chess960BackRank(CHESS960_STANDARD)
+    assert.Nil(t, err)
+    assert.Equal(t, "RNBQKBNR", backRank)
+
+    backRank, err = Chess960BackRank(0)
+    assert.Nil(t, err)
+    assert.Equal(t, "BBQNNRKR", backRank)
+
+    backRank, err = Chess960BackRank(959)
+    assert.Nil(t, err)
+    assert.Equal(t, "RKRNNQBB", backRank)
+
+    _, err = Chess960BackRank(-1)
+    assert.NotNil(t, err)
+    _, err = Chess960BackRank(CHESS960_POSITIONS)
+    assert.NotNil(t, err)
+}
+
+func Test_Chess960BackRank_AllPositions(t *testing.T) {
+    seen := map[string]bool{}
+
+    for number := 0; number < CHESS960_POSITIONS; number++ {
+        backRank, err := Chess960BackRank(number)
+        assert.Nil(t, err)
+        assert.False(t, seen[backRank], backRank)
+        seen[backRank] = true
+
+        for _, letter := range []string{"R", "N", "B"} {
+            assert.Equal(t, 2, strings.Count(backRank, letter), backRank)
+        }
+        assert.Equal(t, 1, strings.Count(backRank, "Q"), backRank)
+
+        bishop := strings.Index(backRank, "B")
+        assert.NotEqual(t, bishop % 2, strings.LastIndex(backRank, "B") % 2, backRank)
+
+        king := strings.Index(backRank, "K")
+        assert.True(t, strings.Index(backRank, "R") < king && king < strings.LastIndex(backRank, "R"), backRank)
+    }
+}
+
+func Test_Chess960_Standard(t *testing.T) {
+    game, err := NewChess960Game(CHESS960_STANDARD)
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, DEFAULT_FEN, fen)
+
+    game, err = NewChess960FourPlayerGame(CHESS960_STANDARD)
+    assert.Nil(t, err)
+    expectedGame, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    gfen, err := game.GFEN()
+    assert.Nil(t, err)
+    expectedGFEN, err := expectedGame.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(t, expectedGFEN, gfen)
+}
+
+func Test_Chess960_FEN(t *testing.T) {
+    game, err := NewChess960Game(0)
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w KQkq - 0 1", fen)
+
+    copied, err := NewSimpleGameFromFEN(fen)
+    assert.Nil(t, err)
+    gfen, err := game.GFEN()
+    assert.Nil(t, err)
+    copiedGFEN, err := copied.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(t, gfen, copiedGFEN)
+}
+
+func Test_Chess960_Random(t *testing.T) {
+    game, err := NewChess960Game(-1)
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    copied, err := NewSimpleGameFromFEN(fen)
+    assert.Nil(t, err)
+
+    state, err := copied.State()
+    assert.Nil(t, err)
+    assert.False(t, state.GameOver)
+}
+
+// every king and rook placement can castle once the rest of the back rank is cleared
+func Test_Chess960_CastleAllPositions(t *testing.T) {
+    white := 0
+
+    for number := 0; number < CHESS960_POSITIONS; number++ {
+        for castle, expected := range map[string][2]int{"O-O": {6, 5}, "O-O-O": {2, 3}} {
+            name := fmt.Sprintf("%d %s", number, castle)
+
+            game, err := NewChess960Game(number)
+            assert.Nil(t, err, name)
+
+            b := game.getBoard()
+            king := findKing(b, white)
+            for x := 0; x < b.x; x++ {
+                piece := b.getPiece(b.getIndex(x, 7))
+                if x == king.x || piece.index == ROOK && (x > king.x) == (castle == "O-O") {
+                    continue
+                }
+
+                b.setPiece(b.getIndex(x, 7), nil)
+            }
+            b.CalculateMoves()
+
+            start, err := game.GFEN()
+            assert.Nil(t, err, name)
+
+            moveKey, err := game.SANToMove(castle)
+            assert.Nil(t, err, name)
+            if err != nil {
+                continue
+            }
+
+            err = game.Execute(moveKey.XFrom, moveKey.YFrom, moveKey.XTo, moveKey.YTo, "")
+            assert.Nil(t, err, name)
+
+            assert.Equal(t, b.getAllPiece(white, KING_U_M), b.getPiece(b.getIndex(expected[0], 7)), name)
+            assert.Equal(t, b.getAllPiece(white, ROOK_M), b.getPiece(b.getIndex(expected[1], 7)), name)
+
+            err = game.Undo()
+            assert.Nil(t, err, name)
+
+            gfen, err := game.GFEN()
+            assert.Nil(t, err, name)
+            assert.Equal(t, start, gfen, name)
+        }
+    }
+}
+
+func Test_Chess960_NoCastleThroughCheck(t *testing.T) {
+    game, err := NewSimpleGameFromFEN("4k3/8/8/8/8/8/8/1K5R w K - 0 1")
+    assert.Nil(t, err)
+    _, err = game.SANToMove("O-O")
+    assert.Nil(t, err)
+
+    // the king passes d1, which the rook doesn't cover after castling
+    game, err = NewSimpleGameFromFEN("3rk3/8/8/8/8/8/8/1K5R w K - 0 1")
+    assert.Nil(t, err)
+    _, err = game.SANToMove("O-O")
+    assert.NotNil(t, err)
+
+    // pawns attack empty squares without having a move there
+    game, err = NewSimpleGameFromFEN("4k3/8/8/8/8/8/3p4/1K5R w K - 0 1")
+    assert.Nil(t, err)
+    _, err = game.SANToMove("O-O")
+    assert.NotNil(t, err)
+}
+
+func Test_Chess960_FourPlayerCastle(t *testing.T) {
+    white := 0
+    red := 1
+
+    game, err := NewChess960FourPlayerGame(0) // BBQNNRKR, the king castles without moving
+    assert.Nil(t, err)
+
+    b := game.getBoard()
+    assert.Equal(t, b.getAllPiece(white, KING_U), b.getPiece(b.getIndex(9, 13)))
+    assert.Equal(t, b.getAllPiece(red, KING_R), b.getPiece(b.getIndex(0, 9)))
+    assert.Equal(t, b.getAllPiece(red, BISHOP), b.getPiece(b.getIndex(0, 3)))
+
+    for x := 3; x <= 8; x++ {
+        b.setPiece(b.getIndex(x, 13), nil)
+    }
+    b.CalculateMoves()
+
+    moveKey, err := game.SANToMove("O-O")
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: 9, YFrom: 13, XTo: 10, YTo: 13}, moveKey)
+
+    err = game.Execute(9, 13, 10, 13, "")
+    assert.Nil(t, err)
+    assert.Equal(t, b.getAllPiece(white, KING_U_M), b.getPiece(b.getIndex(9, 13)))
+    assert.Equal(t, b.getAllPiece(white, ROOK_M), b.getPiece(b.getIndex(8, 13)))
+    assert.Nil(t, b.getPiece(b.getIndex(10, 13)))
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    b.setPiece(b.getIndex(8, 13), b.getAllPiece(white, ROOK))
+    b.setPiece(b.getIndex(10, 13), nil)
+    b.CalculateMoves()
+
+    moveKey, err = game.SANToMove("O-O-O")
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: 9, YFrom: 13, XTo: 8, YTo: 13}, moveKey)
+
+    err = game.Execute(9, 13, 8, 13, "")
+    assert.Nil(t, err)
+    assert.Equal(t, b.getAllPiece(white, KING_U_M), b.getPiece(b.getIndex(5, 13)))
+    assert.Equal(t, b.getAllPiece(white, ROOK_M), b.getPiece(b.getIndex(6, 13)))
+}
+
+func Test_Chess960_Variant(t *testing.T) {
+    definition, err := ParseVariant([]byte(`{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Chess960": true,
+        "Chess960Position": 0,
+        "Pieces": [
+            {"X": 0, "Y": 7, "Color": 0, "Type": "R"},
+            {"X": 1, "Y": 7, "Color": 0, "Type": "N"},
+            {"X": 2, "Y": 7, "Color": 0, "Type": "B"},
+            {"X": 3, "Y": 7, "Color": 0, "Type": "Q"},
+            {"X": 4, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U"},
+            {"X": 5, "Y": 7, "Color": 0, "Type": "B"},
+            {"X": 6, "Y": 7, "Color": 0, "Type": "N"},
+            {"X": 7, "Y": 7, "Color": 0, "Type": "R"},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D"},
+            {"X": 3, "Y": 0, "Color": 1, "Type": "R"}
+        ]
+    }`))
+    assert.Nil(t, err)
+
+    _, err = NewGameFromVariant(definition)
+    assert.NotNil(t, err) // black doesn't have a full back rank
+
+    definition.Pieces = definition.Pieces[:9]
+    definition.Pieces = append(definition.Pieces,
+        VariantPiece{X: 0, Y: 0, Color: 1, Type: "R"},
+        VariantPiece{X: 1, Y: 0, Color: 1, Type: "N"},
+        VariantPiece{X: 2, Y: 0, Color: 1, Type: "B"},
+        VariantPiece{X: 3, Y: 0, Color: 1, Type: "Q"},
+        VariantPiece{X: 5, Y: 0, Color: 1, Type: "B"},
+        VariantPiece{X: 6, Y: 0, Color: 1, Type: "N"},
+        VariantPiece{X: 7, Y: 0, Color: 1, Type: "R"},
+    )
+
+    game, err := NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    gfen, err := game.GFEN()
+    assert.Nil(t, err)
+    expectedGame, err := NewSimpleGameFromFEN("bbqnnrkr/8/8/8/8/8/8/BBQNNRKR w KQkq - 0 1")
+    assert.Nil(t, err)
+    expectedGFEN, err := expectedGame.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(t, expectedGFEN, gfen)
+
+    definition.Chess960Position = CHESS960_POSITIONS
+    _, err = NewGameFromVariant(definition)
+    assert.NotNil(t, err)
+}
+
+func Test_Chess960_VariantFiles(t *testing.T) {
+    for name, newGame := range map[string]func() (Game, error){
+        "chess960": NewSimpleGame,
+        "fourchess960": NewSimpleFourPlayerGame,
+    } {
+        data, err := os.ReadFile("../variants/" + name + ".json")
+        assert.Nil(t, err, name)
+
+        definition, err := ParseVariant(data)
+        assert.Nil(t, err, name)
+        assert.True(t, definition.Chess960, name)
+
+        _, err = NewGameFromVariant(definition) // a random position
+        assert.Nil(t, err, name)
+
+        definition.Chess960Position = CHESS960_STANDARD
+        game, err := NewGameFromVariant(definition)
+        assert.Nil(t, err, name)
+
+        expectedGame, err := newGame()
+        assert.Nil(t, err, name)
+
+        gfen, err := game.GFEN()
+        assert.Nil(t, err, name)
+        expectedGFEN, err := expectedGame.GFEN()
+        assert.Nil(t, err, name)
+        assert.Equal(t, expectedGFEN, gfen, name)
+    }
+}
@@ -0,0 +1,279 @@
+package chess
+
+import (
+    "fmt"
+    "math/rand"
+)
+
+/*
+Responsible for:
+- keeping track of the pieces in hand in crazyhouse games
+- adding drop moves for the pieces in hand
+*/
+
+// called once the pieces are placed, the kings decide which way the dropped pawns face
+func (b *SimpleBoard) setCrazyhouse() {
+    b.crazyhouse = true
+    b.setZobristReserves()
+
+    b.promoted = make([][]bool, b.y)
+    for y := range b.promoted {
+        b.promoted[y] = make([]bool, b.x)
+    }
+
+    for color := 0; color < b.players; color++ {
+        b.dropPawns[color] = -1
+    }
+
+    for y := 0; y < b.y; y++ {
+        for x := 0; x < b.x; x++ {
+            piece := b.pieces[y][x]
+            if piece == nil || !piece.isKing() {
+                continue
+            }
+
+            orientation := piece_types[piece.index].orientation
+            for index, t := range piece_types {
+                if t.pawn && !t.moved && t.orientation == orientation {
+                    b.dropPawns[piece.color] = index
+                    break
+                }
+            }
+        }
+    }
+}
+
+// one key for every count of a piece in hand, a hand never holds more pieces than two linked boards have squares
+func (b *SimpleBoard) setZobristReserves() {
+    counts := 2 * b.x * b.y + 1
+    b.zobristReserves = make([][][]uint64, b.players)
+    for color := range b.zobristReserves {
+        b.zobristReserves[color] = make([][]uint64, totalPieces())
+        for index := range b.zobristReserves[color] {
+            b.zobristReserves[color][index] = make([]uint64, counts)
+            for count := 1; count < counts; count++ {
+                b.zobristReserves[color][index][count] = rand.Uint64()
+            }
+        }
+    }
+}
+
+// captured pieces switch sides, pawns turn to face the capturer and rooks can't castle, kings don't go into the reserve
+func (b *SimpleBoard) reserveIndex(piece *Piece, color int) int {
+    if piece.isKing() {
+        return -1
+    }
+
+    if piece.isPawn() {
+        return b.dropPawns[color]
+    }
+
+    return piece.movedIndex()
+}
+
+// promoted pieces go back into the reserve as the pawns they were
+func (b *SimpleBoard) capturedPiece(piece *Piece, promoted bool) *Piece {
+    if !promoted {
+        return piece
+    }
+
+    return b.getAllPiece(piece.color, PAWN_U)
+}
+
+// sign is 1 when the move is executed and -1 when it is undone
+func (b *SimpleBoard) updateReserves(move *FastMove, sign int) {
+    if move.drop != nil {
+        b.reserves[move.color][move.drop.index] -= sign
+        return
+    } else if b.bughouse {
+        return
+    }
+
+    for i := 1; i < move.oldPiece.count; i++ {
+        piece := move.oldPiece.at(i)
+        if piece == nil || b.allied(piece.color, move.color) {
+            continue
+        }
+
+        piece = b.capturedPiece(piece, move.oldPromoted & (1 << i) != 0)
+        if index := b.reserveIndex(piece, move.color); index >= 0 {
+            b.reserves[move.color][index] += sign
+        }
+    }
+}
+
+// the promoted flags of the changed squares are kept in the move, then the moving piece takes its flag along
+func (b *SimpleBoard) executePromoted(move *FastMove) {
+    move.oldPromoted = 0
+    for i := 0; i < move.location.count; i++ {
+        location := move.location.at(i)
+        if b.promoted[location.y][location.x] {
+            move.oldPromoted |= 1 << i
+            b.promoted[location.y][location.x] = false
+        }
+    }
+
+    // the piece moving on the board lands on the second square, unless it castled or exploded
+    if move.drop == nil && move.newPiece.count > 1 && move.newPiece.at(1) != nil {
+        b.promoted[move.toLocation.y][move.toLocation.x] = move.oldPromoted & 1 != 0 || move.promotionIndex >= 0
+    }
+}
+
+func (b *SimpleBoard) undoPromoted(move *FastMove) {
+    for i := 0; i < move.location.count; i++ {
+        location := move.location.at(i)
+        b.promoted[location.y][location.x] = move.oldPromoted & (1 << i) != 0
+    }
+}
+
+func (b *SimpleBoard) hasReserves() bool {
+    if !b.crazyhouse {
+        return false
+    }
+
+    for _, reserve := range b.reserves {
+        for _, count := range reserve {
+            if count > 0 {
+                return true
+            }
+        }
+    }
+
+    return false
+}
+
+// pieces in hand of each color by their letter
+func (b *SimpleBoard) reservesState() []map[string]int {
+    reserves := make([]map[string]int, b.players)
+    for color, reserve := range b.reserves {
+        reserves[color] = map[string]int{}
+        for index, count := range reserve {
+            if count > 0 {
+                reserves[color][piece_types[index].name] += count
+            }
+        }
+    }
+
+    return reserves
+}
+
+// pawns can't be dropped where they couldn't have moved from or where they would promote
+// pawns dropped one step in front of the edge can still move two steps
+func (b *SimpleBoard) addDrops(color int) {
+    for index, count := range b.reserves[color] {
+        if count <= 0 {
+            continue
+        }
+
+        reserve := b.getAllPiece(color, index)
+
+        for y := 0; y < b.y; y++ {
+            for x := 0; x < b.x; x++ {
+                location := b.getIndex(x, y)
+                if location == nil || b.pieces[y][x] != nil {
+                    continue
+                }
+
+                piece := reserve
+                if reserve.isPawn() {
+                    forward := pawnDirections(index)[0]
+                    ahead := b.getIndex(x + forward.x, y + forward.y)
+                    behind := b.getIndex(x - forward.x, y - forward.y)
+                    if ahead == nil || behind == nil {
+                        continue
+                    }
+
+                    if b.getIndex(x - 2 * forward.x, y - 2 * forward.y) != nil {
+                        piece = b.movePiece(reserve)
+                    }
+                }
+
+                addMoveDrop(b, reserve, piece, location)
+            }
+        }
+    }
+}
+
+func addMoveDrop(
+    b *SimpleBoard,
+    reserve *Piece,
+    piece *Piece,
+    toLocation *Point,
+) {
+    color := piece.color
+    b.drops[color] = append(b.drops[color], FastMove{})
+    move := &b.drops[color][len(b.drops[color]) - 1]
+
+    move.b = b
+    move.fromLocation = nil
+    move.toLocation = toLocation
+    move.color = color
+    move.allyDefense = false
+    move.promotionIndex = -1
+    move.captureValue = 0
+    move.drop = reserve
+
+    target, risk := b.getEnPassant(color)
+    start, end := b.getVulnerable(color)
+
+    move.newPiece.set(piece)
+    move.oldPiece.set(nil)
+    move.location.set(toLocation)
+
+    move.oldTarget = target
+    move.oldRisk = risk
+
+    move.oldStart = start
+    move.oldEnd = end
+}
+
+func dropName(move *FastMove) string {
+    if move.drop == nil {
+        return ""
+    }
+
+    return move.drop.print()
+}
+
+func (s *SimpleGame) Drop(piece string, xTo int, yTo int) error {
+    gameOver := s.p.getGameOver()
+    if gameOver {
+        return fmt.Errorf("game is over")
+    }
+
+    move, err := s.legalDrop(piece, xTo, yTo)
+    if err != nil {
+        return err
+    }
+
+    return s.executeMove(*move)
+}
+
+func (s *SimpleGame) legalDrop(piece string, xTo int, yTo int) (*FastMove, error) {
+    toLocation := s.b.getIndex(xTo, yTo)
+    if toLocation == nil {
+        return nil, fmt.Errorf("invalid to location")
+    }
+
+    legalMoves, err := s.b.LegalMovesOfColor(s.p.getCurrent())
+    if err != nil {
+        return nil, err
+    }
+
+    for i := range legalMoves {
+        if legalMoves[i].toLocation == toLocation && dropName(&legalMoves[i]) == piece {
+            return &legalMoves[i], nil
+        }
+    }
+
+    return nil, fmt.Errorf("invalid drop")
+}
+
+// moves from a move key are either drops or moves on the board
+func (s *SimpleGame) legalMoveKey(moveKey MoveKey) (*FastMove, error) {
+    if moveKey.Drop != "" {
+        return s.legalDrop(moveKey.Drop, moveKey.XTo, moveKey.YTo)
+    }
+
+    return s.legalMove(moveKey.XFrom, moveKey.YFrom, moveKey.XTo, moveKey.YTo, moveKey.Promotion)
+}
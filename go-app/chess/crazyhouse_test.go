@@ -0,0 +1,224 @@
+package chess
+
+import (
+    "os"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_Crazyhouse_CaptureAndDrop(t *testing.T) {
+    white := 0
+    black := 1
+
+    data, err := os.ReadFile("../variants/crazyhouse.json")
+    assert.Nil(t, err)
+    game := newVariantGame(t, string(data))
+    b := game.getBoard()
+
+    for _, san := range []string{"e4", "d5", "exd5", "Qxd5"} {
+        err = game.ExecuteSAN(san)
+        assert.Nil(t, err)
+    }
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []map[string]int{{"P": 1}, {"P": 1}}, state.Reserves)
+
+    hash := b.ZobristHash()
+
+    err = game.Drop("P", 3, 0) // pawns can't be dropped on the last rank
+    assert.NotNil(t, err)
+    err = game.Drop("N", 3, 4) // white has no knight in hand
+    assert.NotNil(t, err)
+    err = game.ExecuteSAN("@d4")
+    assert.Nil(t, err)
+    assert.Equal(t, b.getAllPiece(white, PAWN_U_M), b.getPiece(b.getIndex(3, 4)))
+    assert.Equal(t, 0, b.reserves[white][PAWN_U])
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn, "3. P@d4 *")
+
+    err = game.Undo()
+    assert.Nil(t, err)
+    assert.Nil(t, b.getPiece(b.getIndex(3, 4)))
+    assert.Equal(t, 1, b.reserves[white][PAWN_U])
+    assert.Equal(t, hash, b.ZobristHash())
+
+    err = game.Redo()
+    assert.Nil(t, err)
+    assert.Equal(t, 0, b.reserves[white][PAWN_U])
+
+    err = game.ExecuteSAN("Qxd4")
+    assert.Nil(t, err)
+    assert.Equal(t, 2, b.reserves[black][PAWN_D])
+
+    _, err = game.SANToMove("P@d6") // white has no pawn left
+    assert.NotNil(t, err)
+
+    // the same pieces in hand of another color hash differently
+    hash = b.ZobristHash()
+    b.reserves[white][KNIGHT]++
+    assert.NotEqual(t, hash, b.ZobristHash())
+    b.reserves[white][KNIGHT]--
+    assert.Equal(t, hash, b.ZobristHash())
+}
+
+func Test_Crazyhouse_PromotedCapture(t *testing.T) {
+    white := 0
+    black := 1
+
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Crazyhouse": true,
+        "Pieces": [
+            {"X": 4, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 1, "Color": 0, "Type": "P", "Orientation": "U", "Moved": true},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 1, "Y": 2, "Color": 1, "Type": "N"}
+        ]
+    }`)
+    b := game.getBoard()
+
+    for _, san := range []string{"a8=Q", "Nxa8"} {
+        err := game.ExecuteSAN(san)
+        assert.Nil(t, err)
+    }
+
+    // the captured queen was a pawn
+    assert.Equal(t, 1, b.reserves[black][PAWN_D])
+    assert.Equal(t, 0, b.reserves[black][QUEEN])
+
+    err := game.Undo()
+    assert.Nil(t, err)
+    assert.Equal(t, 0, b.reserves[black][PAWN_D])
+    assert.True(t, b.promoted[0][0])
+
+    err = game.Undo()
+    assert.Nil(t, err)
+    assert.False(t, b.promoted[0][0])
+    assert.Equal(t, b.getAllPiece(white, PAWN_U_M), b.getPiece(b.getIndex(0, 1)))
+
+    // the promoted queen stays a pawn after moving on
+    for _, san := range []string{"a8=Q+", "Kd7", "Qa4+", "Nxa4"} {
+        err := game.ExecuteSAN(san)
+        assert.Nil(t, err, san)
+    }
+    assert.Equal(t, 1, b.reserves[black][PAWN_D])
+}
+
+func Test_Crazyhouse_PawnDrops(t *testing.T) {
+    white := 0
+    black := 1
+
+    game := newVariantGame(t, `{
+        "Width": 5,
+        "Height": 5,
+        "Players": 2,
+        "Crazyhouse": true,
+        "Pieces": [
+            {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 4, "Y": 4, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true}
+        ]
+    }`)
+    b := game.getBoard()
+    b.reserves[white][PAWN_U] = 1
+    b.reserves[black][PAWN_D] = 1
+    b.CalculateMoves()
+
+    dropped := map[int]map[Point]int{white: {}, black: {}}
+    for _, color := range []int{white, black} {
+        for _, move := range b.drops[color] {
+            dropped[color][*move.toLocation] = move.newPiece.array[0].index
+        }
+    }
+
+    assert.Equal(t, 15, len(dropped[white])) // the middle three ranks
+    assert.Equal(t, PAWN_U, dropped[white][Point{2, 3}])
+    assert.Equal(t, PAWN_U_M, dropped[white][Point{2, 2}])
+    assert.Equal(t, PAWN_U_M, dropped[white][Point{2, 1}])
+    assert.Equal(t, 15, len(dropped[black]))
+    assert.Equal(t, PAWN_D, dropped[black][Point{2, 1}])
+    assert.Equal(t, PAWN_D_M, dropped[black][Point{2, 3}])
+
+    err := game.Drop("P", 2, 3)
+    assert.Nil(t, err)
+
+    moves, err := game.Moves(white)
+    assert.Nil(t, err)
+    assert.Contains(t, moves, MoveKey{XFrom: 2, YFrom: 3, XTo: 2, YTo: 1}) // the dropped pawn can move two steps
+}
+
+func Test_Crazyhouse_FourPlayer(t *testing.T) {
+    white := 0
+    red := 1
+
+    game := newVariantGame(t, `{
+        "Width": 6,
+        "Height": 6,
+        "Players": 4,
+        "Crazyhouse": true,
+        "Pieces": [
+            {"X": 5, "Y": 5, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 2, "Y": 3, "Color": 0, "Type": "P", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 1, "Color": 1, "Type": "K", "Orientation": "R", "Moved": true},
+            {"X": 3, "Y": 2, "Color": 1, "Type": "P", "Orientation": "R", "Moved": true},
+            {"X": 5, "Y": 0, "Color": 2, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 3, "Y": 0, "Color": 3, "Type": "K", "Orientation": "L", "Moved": true}
+        ]
+    }`)
+    b := game.getBoard()
+
+    err := game.Execute(2, 3, 3, 2, "")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []map[string]int{{"P": 1}, {}, {}, {}}, state.Reserves)
+    assert.Equal(t, 1, b.reserves[white][PAWN_U])
+
+    // captured pawns face the capturer
+    b.reserves[red][PAWN_R] = 1
+    b.CalculateMoves()
+
+    err = game.Drop("P", 0, 3) // red pawns can't be dropped on the left edge
+    assert.NotNil(t, err)
+    err = game.Drop("P", 1, 3)
+    assert.Nil(t, err)
+    assert.Equal(t, b.getAllPiece(red, PAWN_R), b.getPiece(b.getIndex(1, 3)))
+
+    pgn4, err := game.PGN4()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn4, "P@")
+}
+
+func Test_Crazyhouse_Searcher(t *testing.T) {
+    white := 0
+
+    game := newVariantGame(t, `{
+        "Width": 4,
+        "Height": 4,
+        "Players": 2,
+        "Crazyhouse": true,
+        "Pieces": [
+            {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 2, "Y": 2, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true}
+        ]
+    }`)
+    b := game.getBoard()
+    b.reserves[white][QUEEN] = 1
+    b.CalculateMoves()
+
+    stop := make(chan bool)
+    searcher := newParallelSearcher(b, game.getPlayerCollection(), stop)
+    moveKey, err := searcher.searchWithMinimax(2)
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: -1, YFrom: -1, XTo: 1, YTo: 1, Drop: "Q"}, moveKey)
+
+    san, err := game.MoveToSAN(moveKey)
+    assert.Nil(t, err)
+    assert.Equal(t, "Q@b3#", san)
+}
@@ -0,0 +1,287 @@
+package chess
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+/*
+Responsible for:
+- reading test positions in Extended Position Description
+- measuring the bot against the best move (bm) and avoid move (am) of each position
+*/
+type EPDRecord struct {
+    FEN string
+    Operations map[string][]string // operands of each opcode, for example bm: [Qg6]
+}
+
+type EPDOptions struct {
+    DepthLimit int
+    TimeLimit time.Duration // per position
+}
+
+type EPDResult struct {
+    Id string
+    Move string // the move found by the bot in standard algebraic notation
+    Solved bool
+    Error string // why the record couldn't be judged, for example a malformed bm operand
+    Nodes int64
+    Time time.Duration
+}
+
+type EPDReport struct {
+    Results []EPDResult
+    Solved int
+    Nodes int64
+    Time time.Duration
+}
+
+func ParseEPD(epd string) ([]*EPDRecord, error) {
+    records := []*EPDRecord{}
+
+    for number, line := range strings.Split(epd, "\n") {
+        line = strings.TrimSpace(line)
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+
+        record, err := parseEPDLine(line)
+        if err != nil {
+            return nil, fmt.Errorf("invalid epd at line %d: %v", number + 1, err)
+        }
+
+        records = append(records, record)
+    }
+
+    return records, nil
+}
+
+func parseEPDLine(line string) (*EPDRecord, error) {
+    fields := strings.Fields(line)
+    if len(fields) < 4 {
+        return nil, fmt.Errorf("invalid epd field count")
+    }
+
+    position := strings.Join(fields[:4], " ")
+    rest := line
+    for i := 0; i < 4; i++ {
+        rest = strings.TrimSpace(rest)
+        rest = rest[strings.IndexAny(rest + " ", " \t"):]
+    }
+
+    operations := map[string][]string{}
+    for _, operation := range splitEPDOperations(rest) {
+        operands := splitEPDOperands(operation)
+        if len(operands) == 0 {
+            continue
+        }
+
+        operations[operands[0]] = operands[1:]
+    }
+
+    halfMove := "0"
+    if operands, ok := operations["hmvc"]; ok && len(operands) == 1 {
+        halfMove = operands[0]
+    }
+
+    fullMove := "1"
+    if operands, ok := operations["fmvn"]; ok && len(operands) == 1 {
+        fullMove = operands[0]
+    }
+
+    fen := position + " " + halfMove + " " + fullMove
+
+    _, _, _, err := createSimpleBoardFromFEN(fen)
+    if err != nil {
+        return nil, err
+    }
+
+    return &EPDRecord{
+        FEN: fen,
+        Operations: operations,
+    }, nil
+}
+
+// operations end with semicolons that aren't inside quotes
+func splitEPDOperations(operations string) []string {
+    result := []string{}
+    quoted := false
+    start := 0
+
+    for i := 0; i < len(operations); i++ {
+        if operations[i] == '"' {
+            quoted = !quoted
+        } else if operations[i] == ';' && !quoted {
+            result = append(result, operations[start:i])
+            start = i + 1
+        }
+    }
+
+    if strings.TrimSpace(operations[start:]) != "" {
+        result = append(result, operations[start:])
+    }
+
+    return result
+}
+
+func splitEPDOperands(operation string) []string {
+    operands := []string{}
+
+    for operation = strings.TrimSpace(operation); operation != ""; operation = strings.TrimSpace(operation) {
+        if operation[0] == '"' {
+            end := strings.IndexByte(operation[1:], '"')
+            if end < 0 {
+                end = len(operation) - 1
+            }
+
+            operands = append(operands, operation[1:end+1])
+            operation = operation[min(end+2, len(operation)):]
+            continue
+        }
+
+        end := strings.IndexAny(operation, " \t")
+        if end < 0 {
+            end = len(operation)
+        }
+
+        operands = append(operands, operation[:end])
+        operation = operation[end:]
+    }
+
+    return operands
+}
+
+func RunEPD(records []*EPDRecord, options EPDOptions) (*EPDReport, error) {
+    report := &EPDReport{
+        Results: []EPDResult{},
+    }
+
+    for _, record := range records {
+        result, err := runEPDRecord(record, options)
+        if err != nil {
+            return nil, err
+        }
+
+        report.Results = append(report.Results, *result)
+        report.Nodes += result.Nodes
+        report.Time += result.Time
+        if result.Solved {
+            report.Solved++
+        }
+    }
+
+    return report, nil
+}
+
+func runEPDRecord(record *EPDRecord, options EPDOptions) (*EPDResult, error) {
+    id := record.FEN
+    if operands, ok := record.Operations["id"]; ok && len(operands) > 0 {
+        id = operands[0]
+    }
+
+    game, err := NewSimpleGameFromFEN(record.FEN)
+    if err != nil {
+        return nil, err
+    }
+
+    bot, err := newSimpleBotWithTimeLimit(game, options.DepthLimit, options.TimeLimit)
+    if err != nil {
+        return nil, err
+    }
+
+    start := time.Now()
+    moveKey, err := bot.FindMoveIterativeDeepening()
+    elapsed := time.Since(start)
+
+    result := &EPDResult{
+        Id: id,
+        Nodes: bot.Nodes(),
+        Time: elapsed,
+    }
+
+    if err != nil {
+        return result, nil
+    }
+
+    result.Move, err = game.MoveToSAN(moveKey)
+    if err != nil {
+        return nil, fmt.Errorf("epd %s: %v", id, err)
+    }
+
+    // one malformed record doesn't stop the rest of the suite
+    result.Solved, err = epdSolved(game, record, moveKey)
+    if err != nil {
+        result.Error = err.Error()
+    }
+
+    return result, nil
+}
+
+// the move has to be one of the best moves and none of the avoid moves
+func epdSolved(game Game, record *EPDRecord, moveKey MoveKey) (bool, error) {
+    bestMoves, hasBestMoves := record.Operations["bm"]
+    avoidMoves, hasAvoidMoves := record.Operations["am"]
+    if !hasBestMoves && !hasAvoidMoves {
+        return false, fmt.Errorf("no bm or am operation")
+    }
+
+    if hasBestMoves {
+        found, err := epdContains(game, bestMoves, moveKey)
+        if err != nil || !found {
+            return false, err
+        }
+    }
+
+    if hasAvoidMoves {
+        found, err := epdContains(game, avoidMoves, moveKey)
+        if err != nil || found {
+            return false, err
+        }
+    }
+
+    return true, nil
+}
+
+func epdContains(game Game, sans []string, moveKey MoveKey) (bool, error) {
+    for _, san := range sans {
+        otherMoveKey, err := game.SANToMove(san)
+        if err != nil {
+            return false, fmt.Errorf("invalid move %s: %v", san, err)
+        }
+
+        if otherMoveKey == moveKey {
+            return true, nil
+        }
+    }
+
+    return false, nil
+}
+
+func (r *EPDReport) String() string {
+    var builder strings.Builder
+
+    for _, result := range r.Results {
+        status := "failed"
+        if result.Solved {
+            status = "solved"
+        } else if result.Error != "" {
+            status = "error"
+        }
+
+        move := result.Move
+        if move == "" {
+            move = "-"
+        }
+
+        builder.WriteString(fmt.Sprintf("%s %s %s nodes %d time %v", result.Id, status, move, result.Nodes, result.Time.Round(time.Millisecond)))
+        if result.Error != "" {
+            builder.WriteString(fmt.Sprintf(" %s", result.Error))
+        }
+        builder.WriteString("\n")
+    }
+
+    builder.WriteString(fmt.Sprintf("solved %d of %d nodes %d time %v\n", r.Solved, len(r.Results), r.Nodes, r.Time.Round(time.Millisecond)))
+
+    return builder.String()
+}
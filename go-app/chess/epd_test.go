@@ -0,0 +1,70 @@
+package chess
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_EPD_Parse(t *testing.T) {
+    records, err := ParseEPD(`
+# back rank mates
+6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Ra8#; id "back rank";
+6k1/5ppp/8/8/8/8/8/R5K1 w - - am Ra2 Ra3; hmvc 3; fmvn 20; c0 "quoted ; semicolon";
+`)
+    assert.Nil(t, err)
+    assert.Equal(t, 2, len(records))
+
+    assert.Equal(t, "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", records[0].FEN)
+    assert.Equal(t, []string{"Ra8#"}, records[0].Operations["bm"])
+    assert.Equal(t, []string{"back rank"}, records[0].Operations["id"])
+
+    assert.Equal(t, "6k1/5ppp/8/8/8/8/8/R5K1 w - - 3 20", records[1].FEN)
+    assert.Equal(t, []string{"Ra2", "Ra3"}, records[1].Operations["am"])
+    assert.Equal(t, []string{"quoted ; semicolon"}, records[1].Operations["c0"])
+
+    _, err = ParseEPD("6k1/5ppp/7 w - - bm Ra8#;")
+    assert.NotNil(t, err)
+
+    _, err = ParseEPD("6k1/5ppp/8/8/8/8/8/R5K1 w")
+    assert.NotNil(t, err)
+}
+
+func Test_EPD_Run(t *testing.T) {
+    records, err := ParseEPD(`
+6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Ra8#; id "best move";
+6k1/5ppp/8/8/8/8/8/R5K1 w - - am Ra8#; id "avoid move";
+`)
+    assert.Nil(t, err)
+
+    report, err := RunEPD(records, EPDOptions{DepthLimit: 2, TimeLimit: 5 * time.Second})
+    assert.Nil(t, err)
+
+    assert.Equal(t, 2, len(report.Results))
+    assert.Equal(t, 1, report.Solved)
+    assert.True(t, report.Nodes > 0)
+
+    assert.Equal(t, "best move", report.Results[0].Id)
+    assert.Equal(t, "Ra8#", report.Results[0].Move)
+    assert.True(t, report.Results[0].Solved)
+    assert.False(t, report.Results[1].Solved)
+
+    assert.Contains(t, report.String(), "solved 1 of 2")
+
+    records, err = ParseEPD(`
+6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Rb2; id "malformed";
+6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Ra8#; id "after malformed";
+`)
+    assert.Nil(t, err)
+
+    report, err = RunEPD(records, EPDOptions{DepthLimit: 2, TimeLimit: 5 * time.Second})
+    assert.Nil(t, err)
+
+    assert.Equal(t, 2, len(report.Results))
+    assert.Equal(t, 1, report.Solved)
+    assert.False(t, report.Results[0].Solved)
+    assert.Contains(t, report.Results[0].Error, "invalid move Rb2")
+    assert.True(t, report.Results[1].Solved)
+    assert.Contains(t, report.String(), "malformed error Ra8# ")
+}
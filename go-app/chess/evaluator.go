@@ -35,6 +35,7 @@ func newSimpleEvaluator(b *SimpleBoard, p *SimplePlayerCollection) *SimpleEvalua
         material: make([]int, players),
         mobility: make([]int, players),
         position: make([]int, players),
+        teamScore: make([]int, players),
 
         players: players,
     }
@@ -51,6 +52,7 @@ type SimpleEvaluator struct {
     material []int
     mobility []int
     position []int
+    teamScore []int
 
     players int
 }
@@ -66,8 +68,23 @@ func (e *SimpleEvaluator) eval(score []int) {
         } else {
             for color := range score {
                 score[color] = math.MinInt
+                if e.p.allied(winner, color) {
+                    score[color] = math.MaxInt
+                }
             }
-            score[winner] = math.MaxInt
+        }
+
+        return
+    }
+
+    if e.b.atomic && e.evalExplodedKings(score) {
+        return
+    }
+
+    // dead positions can't be won by anyone
+    if e.b.InsufficientMaterial() {
+        for color := range score {
+            score[color] = 0
         }
 
         return
@@ -84,11 +101,13 @@ func (e *SimpleEvaluator) eval(score []int) {
     }
 
     e.evalMaterial()
+    e.evalPoints()
     e.evalPosition()
+    e.evalHill()
     e.evalMobility()
 
     for color := 0; color < e.players; color++ {
-        if !e.p.playersAlive[color] {
+        if !e.p.playersAlive[color] || (e.b.atomic && e.b.kingLocations[color] == nil) {
             score[color] = math.MinInt
             continue
         }
@@ -107,6 +126,63 @@ func (e *SimpleEvaluator) eval(score []int) {
 
         score[color] = percentage
     }
+
+    e.shareTeamScores(score)
+}
+
+// in atomic games a king can explode before the game is over, the players left with a king win once they are one team
+func (e *SimpleEvaluator) evalExplodedKings(score []int) bool {
+    exploded := false
+    winner := -1
+    for color := 0; color < e.players; color++ {
+        if !e.p.playersAlive[color] {
+            continue
+        }
+
+        if e.b.kingLocations[color] == nil {
+            exploded = true
+        } else if winner < 0 {
+            winner = color
+        } else if !e.p.allied(winner, color) {
+            return false
+        }
+    }
+
+    if !exploded || winner < 0 {
+        return false
+    }
+
+    for color := range score {
+        score[color] = math.MinInt
+        if e.p.allied(winner, color) {
+            score[color] = math.MaxInt
+        }
+    }
+
+    return true
+}
+
+// teammates win or lose together, so every player of a team gets the sum of the team's scores
+func (e *SimpleEvaluator) shareTeamScores(score []int) {
+    if e.p.teams == nil {
+        return
+    }
+
+    for color := 0; color < e.players; color++ {
+        e.teamScore[color] = math.MinInt
+        for other := 0; other < e.players; other++ {
+            if !e.p.allied(color, other) || score[other] == math.MinInt {
+                continue
+            }
+
+            if e.teamScore[color] == math.MinInt {
+                e.teamScore[color] = 0
+            }
+            e.teamScore[color] += score[other]
+        }
+    }
+
+    copy(score, e.teamScore)
 }
 
 func (e *SimpleEvaluator) evalMaterial() {
@@ -123,6 +199,32 @@ func (e *SimpleEvaluator) evalMaterial() {
             e.totalMaterial += value
         }
     }
+
+    if !e.b.crazyhouse {
+        return
+    }
+
+    // pieces in hand can be dropped, so they count as material
+    for color, reserve := range e.b.reserves {
+        for index, count := range reserve {
+            value := piece_types[index].value * count
+            e.material[color] += value
+            e.totalMaterial += value
+        }
+    }
+}
+
+// a point is worth as much material as a pawn
+func (e *SimpleEvaluator) evalPoints() {
+    if e.p.pointsRules == nil {
+        return
+    }
+
+    for color := 0; color < e.players; color++ {
+        value := e.p.getPoints(color) * 100
+        e.material[color] += value
+        e.totalMaterial += value
+    }
 }
 
 func (e *SimpleEvaluator) evalPosition() {
@@ -143,6 +245,22 @@ func (e *SimpleEvaluator) evalPosition() {
     }
 }
 
+func (e *SimpleEvaluator) evalHill() {
+    if e.b.hill == nil {
+        return
+    }
+
+    for color := 0; color < e.players; color++ {
+        distance := e.b.hillDistance(color)
+        if distance < 0 || distance >= len(hill_bonus) || !e.p.playersAlive[color] {
+            continue
+        }
+
+        e.position[color] += hill_bonus[distance]
+        e.totalPosition += hill_bonus[distance]
+    }
+}
+
 func (e *SimpleEvaluator) evalMobility() {
     for color := 0; color < e.players; color++ {
         value := e.b.moves[color].count + e.b.captureMoves[color].count + e.b.defenseMoves[color].count - e.b.queenMoveCount[color]
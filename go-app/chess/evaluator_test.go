@@ -28,6 +28,30 @@ func Test_Eval_Draw(t *testing.T) {
     assert.Equal(t, 0, score[black])
 }
 
+func Test_Eval_InsufficientMaterial(t *testing.T) {
+    white := 0
+    black := 1
+
+    b, _, _, err := createSimpleBoardFromFEN("4k3/8/8/8/8/8/8/2B1K1N1 w - - 0 1")
+    assert.Nil(t, err)
+
+    p, err := newSimplePlayerCollection(2)
+    assert.Nil(t, err)
+
+    evaluator := newSimpleEvaluator(b, p)
+
+    score := make([]int, 2)
+    evaluator.eval(score)
+    assert.True(t, score[white] > 0)
+
+    b.setPiece(b.getIndex(6, 7), nil)
+    b.CalculateMoves()
+
+    evaluator.eval(score)
+    assert.Equal(t, 0, score[white])
+    assert.Equal(t, 0, score[black])
+}
+
 func Test_Eval_Win(t *testing.T) {
     white := 0
     black := 1
@@ -68,6 +92,45 @@ func Test_Eval_Lose(t *testing.T) {
     assert.Equal(t, math.MaxInt, score[black])
 }
 
+func Test_Eval_Teams(t *testing.T) {
+    white := 0
+    black := 1
+    gray := 2
+    blue := 3
+
+    b, err := newSimpleBoard(8, 8, 4)
+    assert.Nil(t, err)
+    b.setTeams([]int{0, 1, 0, 1})
+    b.setPiece(b.getIndex(0, 0), b.getAllPiece(white, KING_U))
+    b.setPiece(b.getIndex(0, 7), b.getAllPiece(black, KING_R))
+    b.setPiece(b.getIndex(7, 0), b.getAllPiece(gray, KING_D))
+    b.setPiece(b.getIndex(7, 7), b.getAllPiece(blue, KING_L))
+    b.setPiece(b.getIndex(3, 3), b.getAllPiece(white, QUEEN))
+    b.setPiece(b.getIndex(5, 5), b.getAllPiece(blue, ROOK))
+    b.CalculateMoves()
+
+    p, err := newSimplePlayerCollection(4)
+    assert.Nil(t, err)
+    p.setTeams([]int{0, 1, 0, 1})
+
+    evaluator := newSimpleEvaluator(b, p)
+
+    score := make([]int, 4)
+    evaluator.eval(score)
+    assert.Equal(t, score[white], score[gray])
+    assert.Equal(t, score[black], score[blue])
+    assert.True(t, score[white] > score[black])
+
+    p.setGameOver(true)
+    p.setWinner(gray)
+
+    evaluator.eval(score)
+    assert.Equal(t, math.MaxInt, score[white])
+    assert.Equal(t, math.MinInt, score[black])
+    assert.Equal(t, math.MaxInt, score[gray])
+    assert.Equal(t, math.MinInt, score[blue])
+}
+
 func Test_EvalMaterial(t *testing.T) {
     white := 0
     black := 1
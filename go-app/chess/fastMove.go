@@ -8,6 +8,10 @@ func addMoveSimple(
     toLocation *Point,
     newPiece *Piece,
 ) {
+    if toPiece != nil && b.atomic && fromPiece.isKing() { // kings can't capture in atomic chess
+        return
+    }
+
     var move *FastMove
     color := fromPiece.color
 
@@ -24,6 +28,7 @@ func addMoveSimple(
     move.toLocation = toLocation
     move.color = fromPiece.color
     move.allyDefense = false
+    move.drop = nil
 
 	target, risk := b.getEnPassant(fromPiece.color)
     start, end := b.getVulnerable(fromPiece.color)
@@ -58,6 +63,10 @@ func addMoveSimple(
 
     move.oldStart = start
     move.oldEnd = end
+
+    if toPiece != nil && b.atomic {
+        b.addExplosion(move)
+    }
 }
 
 func addMoveRevealEnPassant(
@@ -86,6 +95,7 @@ func addMoveRevealEnPassant(
     move.toLocation = toLocation
     move.color = fromPiece.color
     move.allyDefense = false
+    move.drop = nil
 
 	target, risk := b.getEnPassant(fromPiece.color)
     start, end := b.getVulnerable(fromPiece.color)
@@ -120,6 +130,10 @@ func addMoveRevealEnPassant(
 
     move.oldStart = start
     move.oldEnd = end
+
+    if toPiece != nil && b.atomic {
+        b.addExplosion(move)
+    }
 }
 
 func addMoveCaptureEnPassant(
@@ -144,6 +158,7 @@ func addMoveCaptureEnPassant(
     move.toLocation = toLocation
     move.color = fromPiece.color
     move.allyDefense = false
+    move.drop = nil
     move.captureValue = 0
 
 	target, risk := b.getEnPassant(fromPiece.color)
@@ -195,6 +210,10 @@ func addMoveCaptureEnPassant(
         move.location.set(risk2)
         move.captureValue += capturedPiece2.value()
     }
+
+    if b.atomic {
+        b.addExplosion(move)
+    }
 }
 
 func addMoveAllyDefense(
@@ -213,6 +232,7 @@ func addMoveAllyDefense(
     move.toLocation = toLocation
     move.color = fromPiece.color
     move.allyDefense = true
+    move.drop = nil
     move.promotionIndex = -1
     move.captureValue = 0
 }
@@ -238,6 +258,7 @@ func addMoveCastle(
     move.toLocation = toLocation
     move.color = king.color
     move.allyDefense = false
+    move.drop = nil
     move.promotionIndex = -1
     move.captureValue = 0
 
@@ -286,11 +307,13 @@ type FastMove struct {
     allyDefense bool
     promotionIndex int
     captureValue int
+    drop *Piece // the piece taken from the reserve, nil for moves on the board
+    oldPromoted uint64 // bit set of the changed locations that held promoted pieces, kept in crazyhouse
 
     // piece changes
-    newPiece Array4[*Piece]
-    oldPiece Array4[*Piece]
-    location Array4[*Point]
+    newPiece ArrayVar[*Piece]
+    oldPiece ArrayVar[*Piece]
+    location ArrayVar[*Point]
 
     // enPassant
     newTarget *Point
@@ -307,19 +330,44 @@ type FastMove struct {
 
 func (m *FastMove) execute() {
     for i := 0; i < m.newPiece.count; i++ {
-        m.b.setPiece(m.location.array[i], m.newPiece.array[i])
+        m.b.setPiece(m.location.at(i), m.newPiece.at(i))
     }
 
     m.b.setEnPassant(m.color, m.newTarget, m.newRisk)
     m.b.setVulnerable(m.color, m.newStart, m.newEnd)
+
+    if m.b.crazyhouse {
+        m.b.executePromoted(m)
+        m.b.updateReserves(m, 1)
+    }
 }
 
 func (m *FastMove) undo() {
     for i := m.newPiece.count - 1; i >= 0; i-- {
-        m.b.setPiece(m.location.array[i], m.oldPiece.array[i])
+        m.b.setPiece(m.location.at(i), m.oldPiece.at(i))
     }
 
     m.b.setEnPassant(m.color, m.oldTarget, m.oldRisk)
     m.b.setVulnerable(m.color, m.oldStart, m.oldEnd)
+
+    if m.b.crazyhouse {
+        m.b.updateReserves(m, -1)
+        m.b.undoPromoted(m)
+    }
 }
 
+
+// drops have no from location, it is -1
+func createMoveKey(move *FastMove) MoveKey {
+    if move.drop != nil {
+        return MoveKey{XFrom: -1, YFrom: -1, XTo: move.toLocation.x, YTo: move.toLocation.y, Drop: move.drop.print()}
+    }
+
+    return MoveKey{
+        XFrom: move.fromLocation.x,
+        YFrom: move.fromLocation.y,
+        XTo: move.toLocation.x,
+        YTo: move.toLocation.y,
+        Promotion: promotionName(move),
+    }
+}
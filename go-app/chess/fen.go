@@ -0,0 +1,452 @@
+package chess
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+/*
+Responsible for:
+- reading and writing positions of two player games in Forsyth-Edwards Notation
+*/
+const DEFAULT_FEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+func NewSimpleGameFromFEN(fen string) (Game, error) {
+    b, p, fullMove, err := createSimpleBoardFromFEN(fen)
+    if err != nil {
+        return nil, err
+    }
+
+    i, err := invokerFactoryInstance.newSimpleInvoker()
+    if err != nil {
+        return nil, err
+    }
+
+    return &SimpleGame{
+        b: b,
+        p: p,
+        i: i,
+        fullMoveStart: fullMove,
+    }, nil
+}
+
+func createSimpleBoardFromFEN(fen string) (*SimpleBoard, *SimplePlayerCollection, int, error) {
+    white := 0
+    black := 1
+
+    fields := strings.Fields(fen)
+    if len(fields) != 4 && len(fields) != 6 {
+        return nil, nil, 0, fmt.Errorf("invalid fen field count")
+    }
+
+    rows := strings.Split(fields[0], "/")
+    width := -1
+    for _, row := range rows {
+        rowWidth := 0
+        for _, run := range splitFENRow(row) {
+            if empty, err := strconv.Atoi(run); err == nil {
+                rowWidth += empty
+            } else {
+                rowWidth += 1
+            }
+        }
+
+        if width >= 0 && rowWidth != width {
+            return nil, nil, 0, fmt.Errorf("invalid fen row width")
+        }
+        width = rowWidth
+    }
+
+    b, err := newSimpleBoard(width, len(rows), 2)
+    if err != nil {
+        return nil, nil, 0, err
+    }
+
+    for y, row := range rows {
+        x := 0
+        for _, run := range splitFENRow(row) {
+            if empty, err := strconv.Atoi(run); err == nil {
+                x += empty
+                continue
+            }
+
+            color := white
+            if strings.ToLower(run) == run {
+                color = black
+            }
+
+            index, err := fenPieceIndex(run[0], color, y, b.y)
+            if err != nil {
+                return nil, nil, 0, err
+            }
+
+            b.setPiece(b.getIndex(x, y), b.getAllPiece(color, index))
+            x += 1
+        }
+    }
+
+    p, err := newSimplePlayerCollection(2)
+    if err != nil {
+        return nil, nil, 0, err
+    }
+
+    if fields[1] == "w" {
+        p.setCurrent(white)
+    } else if fields[1] == "b" {
+        p.setCurrent(black)
+    } else {
+        return nil, nil, 0, fmt.Errorf("invalid fen side to move")
+    }
+
+    if fields[2] != "-" {
+        for _, right := range fields[2] {
+            err = setFENCastle(b, byte(right))
+            if err != nil {
+                return nil, nil, 0, err
+            }
+        }
+    }
+
+    if fields[3] != "-" {
+        err = setFENEnPassant(b, fields[3], 1 - p.getCurrent())
+        if err != nil {
+            return nil, nil, 0, err
+        }
+    }
+
+    fullMove := 1
+    if len(fields) == 6 {
+        halfMove, err := strconv.Atoi(fields[4])
+        if err != nil || halfMove < 0 {
+            return nil, nil, 0, fmt.Errorf("invalid fen halfmove clock")
+        }
+        p.setHalfMoveClock(halfMove)
+
+        fullMove, err = strconv.Atoi(fields[5])
+        if err != nil || fullMove < 1 {
+            return nil, nil, 0, fmt.Errorf("invalid fen fullmove number")
+        }
+    }
+
+    b.populatePieceSquareTables()
+    b.CalculateMoves()
+
+    return b, p, fullMove, nil
+}
+
+func splitFENRow(row string) []string {
+    runs := []string{}
+
+    for i := 0; i < len(row); i++ {
+        j := i + 1
+        if row[i] >= '0' && row[i] <= '9' {
+            for j < len(row) && row[j] >= '0' && row[j] <= '9' {
+                j++
+            }
+        }
+
+        runs = append(runs, row[i:j])
+        i = j - 1
+    }
+
+    return runs
+}
+
+func fenPieceIndex(letter byte, color int, y int, height int) (int, error) {
+    switch letter {
+    case 'P', 'p':
+        if color == 0 && y == height - 2 {
+            return PAWN_U, nil
+        } else if color == 0 {
+            return PAWN_U_M, nil
+        } else if y == 1 {
+            return PAWN_D, nil
+        } else {
+            return PAWN_D_M, nil
+        }
+    case 'N', 'n':
+        return KNIGHT, nil
+    case 'B', 'b':
+        return BISHOP, nil
+    case 'R', 'r':
+        return ROOK_M, nil
+    case 'Q', 'q':
+        return QUEEN, nil
+    case 'K', 'k':
+        if color == 0 {
+            return KING_U_M, nil
+        }
+        return KING_D_M, nil
+    }
+
+    if index := pieceIndexFromCode(strings.ToUpper(string(letter))); index >= 0 {
+        return index, nil
+    }
+
+    return -1, fmt.Errorf("invalid fen piece %c", letter)
+}
+
+func setFENCastle(b *SimpleBoard, right byte) error {
+    color := 0
+    if right >= 'a' && right <= 'z' {
+        color = 1
+        right = right - 'a' + 'A'
+    }
+
+    kingLocation := findKing(b, color)
+    if kingLocation == nil {
+        return fmt.Errorf("invalid fen castling without king")
+    }
+
+    var rookLocation *Point
+    if right == 'K' {
+        for x := b.x - 1; x > kingLocation.x && rookLocation == nil; x-- {
+            rookLocation = findRook(b, color, x, kingLocation.y)
+        }
+    } else if right == 'Q' {
+        for x := 0; x < kingLocation.x && rookLocation == nil; x++ {
+            rookLocation = findRook(b, color, x, kingLocation.y)
+        }
+    } else if int(right - 'A') < b.x {
+        rookLocation = findRook(b, color, int(right - 'A'), kingLocation.y)
+    }
+
+    if rookLocation == nil {
+        return fmt.Errorf("invalid fen castling right %c", right)
+    }
+
+    king := b.getPiece(kingLocation)
+    b.setPiece(kingLocation, b.getAllPiece(color, unmovedKingIndex(king.index)))
+    b.setPiece(rookLocation, b.getAllPiece(color, ROOK))
+
+    return nil
+}
+
+func setFENEnPassant(b *SimpleBoard, square string, color int) error {
+    target, err := parseSquare(b, square)
+    if err != nil {
+        return err
+    }
+
+    forward := -1
+    if color == 1 {
+        forward = 1
+    }
+
+    risk := b.getIndex(target.x, target.y + forward)
+    piece := b.getPiece(risk)
+    if piece == nil || !piece.isPawn() || piece.color != color {
+        return fmt.Errorf("invalid fen en passant square")
+    }
+
+    b.setEnPassant(color, target, risk)
+
+    return nil
+}
+
+func findKing(b *SimpleBoard, color int) *Point {
+    for y := 0; y < b.y; y++ {
+        for x := 0; x < b.x; x++ {
+            piece := b.pieces[y][x]
+            if piece != nil && piece.color == color && piece.isKing() {
+                return b.getIndex(x, y)
+            }
+        }
+    }
+
+    return nil
+}
+
+func findRook(b *SimpleBoard, color int, x int, y int) *Point {
+    location := b.getIndex(x, y)
+    piece := b.getPiece(location)
+    if piece == nil || piece.color != color || (piece.index != ROOK && piece.index != ROOK_M) {
+        return nil
+    }
+
+    return location
+}
+
+func unmovedKingIndex(index int) int {
+    switch index {
+    case KING_R_M:
+        return KING_R
+    case KING_L_M:
+        return KING_L
+    case KING_D_M:
+        return KING_D
+    case KING_U_M:
+        return KING_U
+    }
+
+    return index
+}
+
+func (s *SimpleGame) FEN() (string, error) {
+    return s.fen(s.fullMove())
+}
+
+func (s *SimpleGame) fen(fullMove int) (string, error) {
+    if s.b.players != 2 {
+        return "", fmt.Errorf("fen requires two players")
+    }
+
+    var builder strings.Builder
+
+    for y := 0; y < s.b.y; y++ {
+        if y > 0 {
+            builder.WriteString("/")
+        }
+
+        empty := 0
+        for x := 0; x < s.b.x; x++ {
+            if s.b.disableds[y][x] {
+                return "", fmt.Errorf("fen does not support disabled squares")
+            }
+
+            piece := s.b.pieces[y][x]
+            if piece == nil {
+                empty++
+                continue
+            }
+
+            if empty > 0 {
+                builder.WriteString(strconv.Itoa(empty))
+                empty = 0
+            }
+
+            if piece.color == 0 {
+                builder.WriteString(piece.print())
+            } else {
+                builder.WriteString(strings.ToLower(piece.print()))
+            }
+        }
+
+        if empty > 0 {
+            builder.WriteString(strconv.Itoa(empty))
+        }
+    }
+
+    if s.p.getCurrent() == 0 {
+        builder.WriteString(" w ")
+    } else {
+        builder.WriteString(" b ")
+    }
+
+    castle := fenCastle(s.b, 0) + strings.ToLower(fenCastle(s.b, 1))
+    if castle == "" {
+        castle = "-"
+    }
+    builder.WriteString(castle)
+
+    enPassant := "-"
+    if target := s.b.enPassantTargets[1 - s.p.getCurrent()]; target != nil {
+        enPassant = squareName(s.b, target)
+    }
+    builder.WriteString(" " + enPassant)
+
+    builder.WriteString(fmt.Sprintf(" %d %d", s.p.getHalfMoveClock(), fullMove))
+
+    return builder.String(), nil
+}
+
+func (s *SimpleGame) fullMove() int {
+    fullMove := max(s.fullMoveStart, 1)
+    previous := -1
+
+    // a new round starts whenever the turn order wraps around
+    for _, command := range s.i.getHistory() {
+        if !command.fullMove {
+            continue
+        }
+
+        position := s.p.turnPosition(command.m.color)
+        if previous >= position {
+            fullMove++
+        }
+        previous = position
+    }
+
+    if previous >= s.p.turnPosition(s.p.getCurrent()) {
+        fullMove++
+    }
+
+    return fullMove
+}
+
+func fenCastle(b *SimpleBoard, color int) string {
+    kingLocation := findKing(b, color)
+    if kingLocation == nil || b.getPiece(kingLocation).moved() {
+        return ""
+    }
+
+    castle := ""
+
+    outermost := true
+    for x := b.x - 1; x > kingLocation.x; x-- {
+        rookLocation := findRook(b, color, x, kingLocation.y)
+        if rookLocation == nil {
+            continue
+        }
+
+        if b.getPiece(rookLocation).index == ROOK && outermost {
+            castle += "K"
+        } else if b.getPiece(rookLocation).index == ROOK {
+            castle += string(rune('A' + x))
+        }
+        outermost = false
+    }
+
+    outermost = true
+    for x := 0; x < kingLocation.x; x++ {
+        rookLocation := findRook(b, color, x, kingLocation.y)
+        if rookLocation == nil {
+            continue
+        }
+
+        if b.getPiece(rookLocation).index == ROOK && outermost {
+            castle += "Q"
+        } else if b.getPiece(rookLocation).index == ROOK {
+            castle += string(rune('A' + x))
+        }
+        outermost = false
+    }
+
+    return castle
+}
+
+func fileName(x int) string {
+    name := ""
+    for x >= 0 {
+        name = string(rune('a' + x % 26)) + name
+        x = x / 26 - 1
+    }
+
+    return name
+}
+
+func squareName(b *SimpleBoard, location *Point) string {
+    return fileName(location.x) + strconv.Itoa(b.y - location.y)
+}
+
+func parseSquare(b *SimpleBoard, square string) (*Point, error) {
+    i := 0
+    x := 0
+    for i < len(square) && square[i] >= 'a' && square[i] <= 'z' {
+        x = x * 26 + int(square[i] - 'a') + 1
+        i++
+    }
+
+    rank, err := strconv.Atoi(square[i:])
+    if i == 0 || err != nil {
+        return nil, fmt.Errorf("invalid square %s", square)
+    }
+
+    location := b.getIndex(x - 1, b.y - rank)
+    if location == nil {
+        return nil, fmt.Errorf("invalid square %s", square)
+    }
+
+    return location, nil
+}
@@ -0,0 +1,127 @@
+package chess
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_FEN_Default(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, DEFAULT_FEN, fen)
+
+    gameFromFEN, err := NewSimpleGameFromFEN(DEFAULT_FEN)
+    assert.Nil(t, err)
+    assert.Equal(t, game.Print(), gameFromFEN.Print())
+
+    moves, err := game.Moves(0)
+    assert.Nil(t, err)
+    movesFromFEN, err := gameFromFEN.Moves(0)
+    assert.Nil(t, err)
+    assert.Equal(t, moves, movesFromFEN)
+}
+
+func Test_FEN_AfterMoves(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 6, 4, 4, "") // white pawn advance
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", fen)
+
+    err = game.Execute(6, 0, 5, 2, "") // black knight advance
+    assert.Nil(t, err)
+    err = game.Execute(7, 6, 7, 5, "") // white pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(7, 0, 6, 0, "") // black rook advance
+    assert.Nil(t, err)
+
+    fen, err = game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "rnbqkbr1/pppppppp/5n2/8/4P3/7P/PPPP1PP1/RNBQKBNR w KQq - 1 3", fen)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    fen, err = game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "rnbqkb1r/pppppppp/5n2/8/4P3/7P/PPPP1PP1/RNBQKBNR b KQkq - 0 2", fen)
+}
+
+func Test_FEN_RoundTrip(t *testing.T) {
+    for _, fen := range []string{
+        "r3k2r/8/8/8/8/8/8/R3K2R w Kq - 0 1",
+        "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 12",
+        "8/8/8/8/8/8/8/K1k5 b - - 0 40",
+        "rnbqk/ppppp/5/PPPPP/RNBQK w Qq - 0 1",
+    } {
+        game, err := NewSimpleGameFromFEN(fen)
+        assert.Nil(t, err)
+
+        actual, err := game.FEN()
+        assert.Nil(t, err)
+        assert.Equal(t, fen, actual)
+    }
+}
+
+func Test_FEN_CastlingRights(t *testing.T) {
+    game, err := NewSimpleGameFromFEN("r3k2r/8/8/8/8/8/8/R3K2R w Kq - 0 1")
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 7, 0, 7, "") // white queen side castle
+    assert.NotNil(t, err)
+
+    err = game.Execute(4, 7, 7, 7, "") // white king side castle
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 0, 7, 0, "") // black king side castle
+    assert.NotNil(t, err)
+
+    err = game.Execute(4, 0, 0, 0, "") // black queen side castle
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "2kr3r/8/8/8/8/8/8/R4RK1 w - - 2 2", fen)
+}
+
+func Test_FEN_EnPassant(t *testing.T) {
+    game, err := NewSimpleGameFromFEN("4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1")
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 3, 3, 2, "") // white pawn capture en passant
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "4k3/8/3P4/8/8/8/8/4K3 b - - 0 1", fen)
+}
+
+func Test_FEN_Invalid(t *testing.T) {
+    for _, fen := range []string{
+        "",
+        "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0",
+        "rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+        "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNX w KQkq - 0 1",
+        "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
+        "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBN1 w KQkq - 0 1",
+        "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq e3 0 1",
+        "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 0",
+    } {
+        _, err := NewSimpleGameFromFEN(fen)
+        assert.NotNil(t, err, fen)
+    }
+
+    game, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    _, err = game.FEN()
+    assert.NotNil(t, err)
+}
@@ -1,6 +1,10 @@
 package chess
 
-import "fmt"
+import (
+    "fmt"
+    "math/rand"
+    "time"
+)
 
 /*
 Responsible for:
@@ -9,13 +13,26 @@ Responsible for:
 type Game interface {
     // these are for the hub
 	Execute(xFrom int, yFrom int, xTo int, yTo int, promotion string) error // called when a player tries to make a move
+    ExecuteSAN(san string) error // called when a player tries to make a move in standard algebraic notation
     State() (*BoardData, error) // called to get the game state
     View(xFrom int, yFrom int) (*PieceState, error) // show valid moves of piece
     Moves(color int) ([]MoveKey, error) // get all valid moves
+    Drop(piece string, xTo int, yTo int) error // called when a player drops a piece from their reserve
 	Undo() error
 	Redo() error
 	Print() string
     Copy() (Game, error)
+    Resign(color int) error // eliminates the player, the last player remaining wins
+    OfferDraw(color int) error // the game is drawn once every remaining player offered or accepted
+    AcceptDraw(color int) error
+    HistoryLength() int // number of commands that can be undone, used to line up state kept outside of the game
+    FEN() (string, error) // get the position in forsyth-edwards notation
+    GFEN() (string, error) // get the position in generalized forsyth-edwards notation
+    PGN() (string, error) // get the game history in portable game notation
+    PGN4() (string, error) // get the game history of a four player game in pgn4 notation
+    MoveToSAN(moveKey MoveKey) (string, error) // get a move in standard algebraic notation
+    MoveToLAN(moveKey MoveKey) (string, error) // get a move in long algebraic notation
+    SANToMove(san string) (MoveKey, error) // get a move of the current player from standard algebraic notation
 
     getBoard() *SimpleBoard
     getPlayerCollection() *SimplePlayerCollection
@@ -117,6 +134,12 @@ type SimpleGame struct {
 	b *SimpleBoard
     p *SimplePlayerCollection
 	i Invoker
+    fullMoveStart int
+    tags map[string]string
+    startPosition uint64 // hash of the position before the first command
+    positions []uint64 // hash after each command in the history that ends a turn, otherwise 0
+    captures []*CaptureData // pieces captured by the last move on a bughouse board
+    random *rand.Rand // picks the moves of eliminated players whose pieces move randomly, seeded from the time when nil
 }
 
 func (s *SimpleGame) State() (*BoardData, error) {
@@ -131,28 +154,64 @@ func (s *SimpleGame) State() (*BoardData, error) {
     gameOver := s.p.getGameOver()
     boardData.GameOver = gameOver
 
+    boardData.HalfMoveClock = s.p.getHalfMoveClock()
+
+    drawOffers := []int{}
+    for color := 0; color < s.p.getPlayers(); color++ {
+        if s.p.drawOffered(color) {
+            drawOffers = append(drawOffers, color)
+        }
+    }
+    boardData.DrawOffers = drawOffers
+
+    teams := []int{}
+    if s.p.teams != nil {
+        teams = append(teams, s.p.teams...)
+    }
+    boardData.Teams = teams
+    boardData.WinningTeam = s.p.getTeam(winningPlayer)
+
+    points := []int{}
+    ranking := []int{}
+    if s.p.pointsRules != nil {
+        points = append(points, s.p.points...)
+        ranking = s.p.ranking()
+    }
+    boardData.Points = points
+    boardData.Ranking = ranking
+    boardData.Checks = s.p.checksState()
+
     return boardData, nil
 }
 
 func (s *SimpleGame) Execute(xFrom int, yFrom int, xTo int, yTo int, promotion string) error {
+    gameOver := s.p.getGameOver()
+    if gameOver {
+        return fmt.Errorf("game is over")
+    }
+
+    move, err := s.legalMove(xFrom, yFrom, xTo, yTo, promotion)
+    if err != nil {
+        return err
+    }
+
+    return s.executeMove(*move)
+}
+
+func (s *SimpleGame) legalMove(xFrom int, yFrom int, xTo int, yTo int, promotion string) (*FastMove, error) {
     fromLocation := s.b.getIndex(xFrom, yFrom)
     if fromLocation == nil {
-        return fmt.Errorf("invalid from location")
+        return nil, fmt.Errorf("invalid from location")
     }
 
     toLocation := s.b.getIndex(xTo, yTo)
     if toLocation == nil {
-        return fmt.Errorf("invalid to location")
-    }
-
-    gameOver := s.p.getGameOver()
-    if gameOver {
-        return fmt.Errorf("game is over")
+        return nil, fmt.Errorf("invalid to location")
     }
 
     moves, err := s.b.LegalMovesOfLocation(fromLocation)
     if err != nil {
-        return err
+        return nil, err
     }
 
     found := false
@@ -161,7 +220,7 @@ func (s *SimpleGame) Execute(xFrom int, yFrom int, xTo int, yTo int, promotion s
         promotionString := ""
 
         if m.promotionIndex >= 0 {
-            promotionString = piece_names[m.promotionIndex]
+            promotionString = piece_types[m.promotionIndex].name
         }
 
         if m.fromLocation == fromLocation && m.toLocation == toLocation && promotionString == promotion {
@@ -177,33 +236,90 @@ func (s *SimpleGame) Execute(xFrom int, yFrom int, xTo int, yTo int, promotion s
     }
 
     if !found {
-        return fmt.Errorf("invalid move")
+        return nil, fmt.Errorf("invalid move")
     }
 
     if move.allyDefense {
-        return fmt.Errorf("AllyDefenseMove not possible")
+        return nil, fmt.Errorf("AllyDefenseMove not possible")
     }
 
+    return &move, nil
+}
+
+func (s *SimpleGame) executeMove(move FastMove) error {
+    s.recordStartPosition()
+
     transition := PlayerTransition{}
     createPlayerTransition(s.b, s.p, false, false, &transition)
+    transition.countHalfMove(s.b, &move)
+    transition.keepDrawOffer(move.color)
+
+    // checks are only known once the move is made
+    if s.p.pointsRules != nil || s.p.checkLimit > 0 {
+        move.execute()
+        s.b.CalculateMoves()
+        transition.countPoints(s.b, &move)
+        transition.countChecks(s.b, &move)
+        move.undo()
+    }
+
+    s.captures = bughouseCaptures(s.b, &move)
 
-    err = s.i.execute(move, transition)
+    err := s.i.execute(move, transition)
     if err != nil {
         return err
     }
 
     s.b.CalculateMoves()
 
-    for {
+    return s.finishTurn(move.color)
+}
+
+// eliminate checkmated players, then end the game if the position is drawn
+// the mover scores points for the players they eliminate, -1 when nobody moved
+func (s *SimpleGame) finishTurn(mover int) error {
+    transition := PlayerTransition{}
+
+    for !s.p.getGameOver() {
         currentPlayer := s.p.getCurrent()
+        if s.p.movesRandomly(currentPlayer) {
+            break
+        }
+
+        // a king on the hill wins before anyone is checkmated
+        createPlayerTransition(s.b, s.p, false, false, &transition)
+        if mover >= 0 && transition.reachHill(s.b, mover) {
+            err := s.i.executeHalf(transition)
+            if err != nil {
+                return err
+            }
+
+            continue
+        }
+
+        // a player checked too often by one opponent is out, even when it isn't their turn
+        if checked := s.p.checkedOut(); checked >= 0 {
+            createChecksTransition(s.b, s.p, checked, &transition)
+
+            err := s.i.executeHalf(transition)
+            if err != nil {
+                return err
+            }
+
+            s.b.CalculateMoves()
+
+            continue
+        }
 
         checkmate, stalemate, err := s.b.CheckmateAndStalemate(currentPlayer)
         if err != nil {
             return err
         }
 
-        if checkmate {
+        // with points stalemated players are eliminated instead of drawing the game
+        if checkmate || (stalemate && s.p.pointsRules != nil) {
             createPlayerTransition(s.b, s.p, true, false, &transition)
+            transition.countEliminationPoints(mover, checkmate)
 
             err = s.i.executeHalf(transition)
             if err != nil {
@@ -223,9 +339,169 @@ func (s *SimpleGame) Execute(xFrom int, yFrom int, xTo int, yTo int, promotion s
         break
     }
 
+    repetition := s.recordPosition() >= 3
+    fiftyMoves := s.p.getHalfMoveClock() >= s.p.getHalfMoveLimit()
+    deadPosition := s.b.InsufficientMaterial()
+
+    if (repetition || fiftyMoves || deadPosition) && !s.p.getGameOver() {
+        createPlayerTransition(s.b, s.p, false, true, &transition)
+
+        err := s.i.executeHalf(transition)
+        if err != nil {
+            return err
+        }
+    }
+
+    if s.p.claimWin() && !s.p.getGameOver() {
+        createClaimWinTransition(s.b, s.p, &transition)
+
+        err := s.i.executeHalf(transition)
+        if err != nil {
+            return err
+        }
+    }
+
+    if s.p.movesRandomly(s.p.getCurrent()) && !s.p.getGameOver() {
+        return s.executeRandomMove(s.p.getCurrent())
+    }
+
     return nil
 }
 
+// eliminated players whose pieces move randomly make a random legal move, or pass without one
+func (s *SimpleGame) executeRandomMove(color int) error {
+    legalMoves, err := s.b.LegalMovesOfColor(color)
+    if err != nil {
+        return err
+    }
+
+    if len(legalMoves) > 0 {
+        if s.random == nil {
+            s.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+        }
+
+        return s.executeMove(legalMoves[s.random.Intn(len(legalMoves))])
+    }
+
+    transition := PlayerTransition{}
+    createPlayerTransition(s.b, s.p, false, false, &transition)
+
+    err = s.i.executeHalf(transition)
+    if err != nil {
+        return err
+    }
+
+    return s.finishTurn(-1)
+}
+
+func (s *SimpleGame) Resign(color int) error {
+    err := s.checkPlayer(color)
+    if err != nil {
+        return err
+    }
+
+    s.recordStartPosition()
+
+    transition := PlayerTransition{}
+    createResignTransition(s.b, s.p, color, &transition)
+
+    err = s.i.executeHalf(transition)
+    if err != nil {
+        return err
+    }
+
+    s.b.CalculateMoves()
+
+    return s.finishTurn(-1)
+}
+
+func (s *SimpleGame) OfferDraw(color int) error {
+    err := s.checkPlayer(color)
+    if err != nil {
+        return err
+    }
+
+    if s.p.drawOffered(color) {
+        return fmt.Errorf("draw already offered")
+    }
+
+    transition := PlayerTransition{}
+    createDrawTransition(s.b, s.p, color, &transition)
+
+    return s.i.executeHalf(transition)
+}
+
+func (s *SimpleGame) AcceptDraw(color int) error {
+    err := s.checkPlayer(color)
+    if err != nil {
+        return err
+    }
+
+    offered := false
+    for other := 0; other < s.p.getPlayers(); other++ {
+        if other != color && s.p.playersAlive[other] && s.p.drawOffered(other) {
+            offered = true
+        }
+    }
+
+    if !offered || s.p.drawOffered(color) {
+        return fmt.Errorf("no draw offer to accept")
+    }
+
+    transition := PlayerTransition{}
+    createDrawTransition(s.b, s.p, color, &transition)
+
+    return s.i.executeHalf(transition)
+}
+
+func (s *SimpleGame) checkPlayer(color int) error {
+    if s.p.getGameOver() {
+        return fmt.Errorf("game is over")
+    }
+
+    if s.p.colorOutOfBounds(color) || !s.p.playersAlive[color] {
+        return fmt.Errorf("invalid player")
+    }
+
+    return nil
+}
+
+// the position before any command is the start position
+func (s *SimpleGame) recordStartPosition() {
+    if len(s.i.getHistory()) == 0 {
+        s.startPosition = s.positionHash()
+    }
+}
+
+// remember the position after the last command, and count how often it occurred
+func (s *SimpleGame) recordPosition() int {
+    length := len(s.i.getHistory())
+    for len(s.positions) < length {
+        s.positions = append(s.positions, 0)
+    }
+    s.positions = s.positions[:length]
+
+    hash := s.positionHash()
+    s.positions[length - 1] = hash
+
+    count := 0
+    if s.startPosition == hash {
+        count++
+    }
+
+    for _, position := range s.positions {
+        if position == hash {
+            count++
+        }
+    }
+
+    return count
+}
+
+func (s *SimpleGame) positionHash() uint64 {
+    return s.b.ZobristHash() ^ s.p.ZobristHash()
+}
+
 func (s *SimpleGame) View(x int, y int) (*PieceState, error) {
     location := s.b.getIndex(x, y)
 
@@ -301,13 +577,7 @@ func (s *SimpleGame) Moves(color int) ([]MoveKey, error) {
     }
 
     for _, move := range moves {
-        moveKeys = append(moveKeys, MoveKey{
-            XFrom: move.fromLocation.x,
-            YFrom: move.fromLocation.y,
-            XTo: move.toLocation.x,
-            YTo: move.toLocation.y,
-            Promotion: "",
-        })
+        moveKeys = append(moveKeys, createMoveKey(&move))
     }
 
     return moveKeys, nil
@@ -319,9 +589,14 @@ func (s *SimpleGame) Undo() error {
         return err
     }
 
+    // random moves are undone together with the move before them
+    for err == nil && s.p.movesRandomly(s.p.getCurrent()) && !s.p.getGameOver() {
+        err = s.i.undo()
+    }
+
     s.b.CalculateMoves()
 
-    return nil
+    return err
 }
 
 func (s *SimpleGame) Redo() error {
@@ -330,9 +605,17 @@ func (s *SimpleGame) Redo() error {
         return err
     }
 
+    for err == nil && s.p.movesRandomly(s.p.getCurrent()) && !s.p.getGameOver() {
+        err = s.i.redo()
+    }
+
     s.b.CalculateMoves()
 
-    return nil
+    return err
+}
+
+func (s *SimpleGame) HistoryLength() int {
+    return len(s.i.getHistory())
 }
 
 func (s *SimpleGame) Print() string {
@@ -359,9 +642,40 @@ func (s *SimpleGame) Copy() (Game, error) {
         b: newBoard,
         p: newPlayerCollection,
         i: newInvoker,
+        fullMoveStart: s.fullMove(),
     }, nil
 }
 
+// undo the whole history, then redo it while visiting each command before it executes
+func (s *SimpleGame) replay(visit func(index int, command *Command) error) error {
+    history := s.i.getHistory()
+
+    for i := len(history) - 1; i >= 0; i-- {
+        if history[i].fullMove {
+            history[i].m.undo()
+        }
+        history[i].p.undo()
+    }
+
+    var err error
+    for i := range history {
+        s.b.CalculateMoves()
+
+        if err == nil {
+            err = visit(i, &history[i])
+        }
+
+        if history[i].fullMove {
+            history[i].m.execute()
+        }
+        history[i].p.execute()
+    }
+
+    s.b.CalculateMoves()
+
+    return err
+}
+
 func (s *SimpleGame) getBoard() *SimpleBoard {
     return s.b
 }
@@ -164,6 +164,24 @@ func Test_CastleWhenBlocked(t *testing.T) {
 	assert.Equal(t, expectedPrintedBoard, actualPrintedBoard)
 }
 
+func Test_NoCastleOutOfCheck(t *testing.T) {
+    // the rook is calculated after the black king, so the check has to be caught when the castle is tried
+    game, err := NewSimpleGameFromFEN("r3k2r/8/8/8/8/8/8/4R1K1 b kq - 0 1")
+    assert.Nil(t, err)
+
+    _, err = game.SANToMove("O-O")
+    assert.NotNil(t, err)
+
+    _, err = game.SANToMove("O-O-O")
+    assert.NotNil(t, err)
+
+    game, err = NewSimpleGameFromFEN("r3k2r/8/8/8/8/8/8/5RK1 b kq - 0 1")
+    assert.Nil(t, err)
+
+    _, err = game.SANToMove("O-O-O")
+    assert.Nil(t, err)
+}
+
 func Test_CastleCanUndo(t *testing.T) {
     white := 0
     black := 1
@@ -534,6 +552,303 @@ func Test_Stalemate(t *testing.T) {
     assert.Equal(t, true, state.GameOver)
 }
 
+func Test_ThreefoldRepetition(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    for i := 0; i < 2; i++ {
+        err = game.Execute(6, 7, 5, 5, "") // white knight out
+        assert.Nil(t, err)
+        err = game.Execute(6, 0, 5, 2, "") // black knight out
+        assert.Nil(t, err)
+        err = game.Execute(5, 5, 6, 7, "") // white knight back
+        assert.Nil(t, err)
+
+        state, err := game.State()
+        assert.Nil(t, err)
+        assert.Equal(t, false, state.GameOver)
+
+        err = game.Execute(5, 2, 6, 0, "") // black knight back
+        assert.Nil(t, err)
+    }
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, -1, state.WinningPlayer)
+    assert.Equal(t, true, state.GameOver)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Redo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, true, state.GameOver)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    err = game.Execute(1, 0, 2, 2, "") // black plays a different knight instead
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, false, state.GameOver)
+}
+
+func Test_FiftyMoveRule(t *testing.T) {
+    game, err := NewSimpleGameFromFEN("4k3/8/8/8/8/8/4P3/R3K3 w - - 98 80")
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 6, 4, 5, "") // white pawn advance resets the clock
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 0, state.HalfMoveClock)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    err = game.Execute(0, 7, 0, 6, "") // white rook move
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 99, state.HalfMoveClock)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Execute(4, 0, 3, 0, "") // black king move
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 100, state.HalfMoveClock)
+    assert.Equal(t, -1, state.WinningPlayer)
+    assert.Equal(t, true, state.GameOver)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 99, state.HalfMoveClock)
+    assert.Equal(t, false, state.GameOver)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "4k3/8/8/8/8/8/R3P3/4K3 b - - 99 80", fen)
+
+    err = game.Redo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, true, state.GameOver)
+}
+
+func Test_InsufficientMaterialDraw(t *testing.T) {
+    game, err := NewSimpleGameFromFEN("4k3/8/8/8/8/8/3r4/4K1N1 w - - 0 1")
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 7, 3, 6, "") // white king captures the last rook
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, -1, state.WinningPlayer)
+    assert.Equal(t, true, state.GameOver)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, false, state.GameOver)
+}
+
+func Test_Resign(t *testing.T) {
+    white := 0
+    black := 1
+
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 6, 4, 4, "") // white pawn advance
+    assert.Nil(t, err)
+
+    err = game.Resign(white)
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, black, state.WinningPlayer)
+    assert.Equal(t, true, state.GameOver)
+
+    err = game.Resign(black)
+    assert.NotNil(t, err)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, white, state.CurrentPlayer)
+    assert.Equal(t, false, state.GameOver)
+    for _, piece := range state.Pieces {
+        assert.Equal(t, false, piece.D)
+    }
+}
+
+func Test_ResignUndoWithoutMoves(t *testing.T) {
+    white := 0
+    black := 1
+
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    err = game.Resign(white)
+    assert.Nil(t, err)
+
+    // nothing is reverted when there is no move before the resign
+    err = game.Undo()
+    assert.NotNil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, black, state.WinningPlayer)
+    assert.Equal(t, true, state.GameOver)
+
+    err = game.Redo()
+    assert.NotNil(t, err)
+
+    red := 0
+    yellow := 2
+
+    game, err = NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    moves, err := game.Moves(red)
+    assert.Nil(t, err)
+    expected := len(moves)
+
+    err = game.Resign(yellow)
+    assert.Nil(t, err)
+
+    err = game.Undo()
+    assert.NotNil(t, err)
+
+    moves, err = game.Moves(red)
+    assert.Nil(t, err)
+    assert.Equal(t, expected, len(moves))
+}
+
+func Test_ResignFourPlayer(t *testing.T) {
+    red := 0
+    blue := 1
+    yellow := 2
+    green := 3
+
+    game, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    err = game.Resign(yellow) // not yellows turn
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, red, state.CurrentPlayer)
+    assert.Equal(t, false, state.GameOver)
+    for _, piece := range state.Pieces {
+        assert.Equal(t, piece.C == yellow, piece.D)
+    }
+
+    err = game.Execute(4, 12, 4, 11, "") // red pawn advance
+    assert.Nil(t, err)
+
+    err = game.Resign(blue)
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, green, state.CurrentPlayer)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Resign(green)
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, red, state.WinningPlayer)
+    assert.Equal(t, true, state.GameOver)
+
+    pgn4, err := game.PGN4()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn4, "1. R:Yellow .. e2-e3 .. R .. R\n1-0-0-0")
+
+    importedGame, err := NewSimpleGameFromPGN4(pgn4)
+    assert.Nil(t, err)
+
+    importedPGN4, err := importedGame.PGN4()
+    assert.Nil(t, err)
+    assert.Equal(t, pgn4, importedPGN4)
+}
+
+func Test_Draw(t *testing.T) {
+    white := 0
+    black := 1
+
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    err = game.AcceptDraw(black)
+    assert.NotNil(t, err)
+
+    err = game.OfferDraw(white)
+    assert.Nil(t, err)
+
+    err = game.OfferDraw(white)
+    assert.NotNil(t, err)
+
+    err = game.Execute(4, 6, 4, 4, "") // white pawn advance keeps the offer
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []int{white}, state.DrawOffers)
+
+    err = game.Execute(4, 1, 4, 3, "") // black pawn advance declines the offer
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []int{}, state.DrawOffers)
+
+    err = game.OfferDraw(white)
+    assert.Nil(t, err)
+
+    err = game.AcceptDraw(black)
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, -1, state.WinningPlayer)
+    assert.Equal(t, true, state.GameOver)
+
+    err = game.Execute(6, 7, 5, 5, "")
+    assert.NotNil(t, err)
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn, "1. e4 e5 1/2-1/2")
+}
+
 func Test_NewSimpleGame(t *testing.T) {
 	game, err := NewSimpleGame()
 	assert.Nil(t, err)
@@ -0,0 +1,326 @@
+package chess
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+/*
+Responsible for:
+- reading and writing positions of any board size and player count
+
+A generalized fen has eight space separated fields:
+- board size, for example 14x14
+- current player color, for example 0
+- alive flags of each color, for example 1,1,0,1
+- rows from the top of the board separated by slashes, with cells separated by commas
+    - a number is a run of empty squares, for example 3
+    - a number followed by x is a run of disabled squares, for example 3x
+    - a color followed by a piece is a piece, for example 0PU or 2KDm
+    - pawns and kings are followed by their direction (U, D, L, or R)
+    - moved pawns, rooks, and kings are followed by m
+- en passant target and risk of each color, for example -,e3:e4,-,-
+- vulnerable start and end of each color, for example -,-,f1:g1,-
+- halfmove clock, for example 0
+- fullmove number, for example 1
+
+Eliminated colors are read back as walls, so games where eliminated pieces are removed, zombies, or random movers can't be written.
+*/
+func NewSimpleGameFromGFEN(gfen string) (Game, error) {
+    b, p, fullMove, err := createSimpleBoardFromGFEN(gfen)
+    if err != nil {
+        return nil, err
+    }
+
+    i, err := invokerFactoryInstance.newSimpleInvoker()
+    if err != nil {
+        return nil, err
+    }
+
+    return &SimpleGame{
+        b: b,
+        p: p,
+        i: i,
+        fullMoveStart: fullMove,
+    }, nil
+}
+
+func createSimpleBoardFromGFEN(gfen string) (*SimpleBoard, *SimplePlayerCollection, int, error) {
+    fields := strings.Fields(gfen)
+    if len(fields) != 8 {
+        return nil, nil, 0, fmt.Errorf("invalid gfen field count")
+    }
+
+    size := strings.Split(fields[0], "x")
+    if len(size) != 2 {
+        return nil, nil, 0, fmt.Errorf("invalid gfen board size")
+    }
+    x, errX := strconv.Atoi(size[0])
+    y, errY := strconv.Atoi(size[1])
+    if errX != nil || errY != nil {
+        return nil, nil, 0, fmt.Errorf("invalid gfen board size")
+    }
+
+    alive := strings.Split(fields[2], ",")
+    players := len(alive)
+
+    b, err := newSimpleBoard(x, y, players)
+    if err != nil {
+        return nil, nil, 0, err
+    }
+
+    p, err := newSimplePlayerCollection(players)
+    if err != nil {
+        return nil, nil, 0, err
+    }
+
+    current, err := strconv.Atoi(fields[1])
+    if err != nil || p.colorOutOfBounds(current) {
+        return nil, nil, 0, fmt.Errorf("invalid gfen current player")
+    }
+    p.setCurrent(current)
+
+    for color, flag := range alive {
+        if flag == "0" {
+            p.eliminate(color)
+            b.disablePieces(color, true)
+        } else if flag != "1" {
+            return nil, nil, 0, fmt.Errorf("invalid gfen alive flag")
+        }
+    }
+
+    rows := strings.Split(fields[3], "/")
+    if len(rows) != b.y {
+        return nil, nil, 0, fmt.Errorf("invalid gfen row count")
+    }
+
+    for yi, row := range rows {
+        xi, err := setGFENRow(b, row, yi)
+        if err != nil {
+            return nil, nil, 0, err
+        }
+        if xi != b.x {
+            return nil, nil, 0, fmt.Errorf("invalid gfen row width")
+        }
+    }
+
+    enPassants := strings.Split(fields[4], ",")
+    vulnerables := strings.Split(fields[5], ",")
+    if len(enPassants) != players || len(vulnerables) != players {
+        return nil, nil, 0, fmt.Errorf("invalid gfen square count")
+    }
+
+    for color := 0; color < players; color++ {
+        target, risk, err := parseGFENSquares(b, enPassants[color])
+        if err != nil {
+            return nil, nil, 0, err
+        }
+        b.setEnPassant(color, target, risk)
+
+        start, end, err := parseGFENSquares(b, vulnerables[color])
+        if err != nil {
+            return nil, nil, 0, err
+        }
+        b.setVulnerable(color, start, end)
+    }
+
+    halfMove, err := strconv.Atoi(fields[6])
+    if err != nil || halfMove < 0 {
+        return nil, nil, 0, fmt.Errorf("invalid gfen halfmove clock")
+    }
+    p.setHalfMoveClock(halfMove)
+
+    fullMove, err := strconv.Atoi(fields[7])
+    if err != nil || fullMove < 1 {
+        return nil, nil, 0, fmt.Errorf("invalid gfen fullmove number")
+    }
+
+    b.populatePieceSquareTables()
+    b.CalculateMoves()
+
+    return b, p, fullMove, nil
+}
+
+func setGFENRow(b *SimpleBoard, row string, y int) (int, error) {
+    x := 0
+
+    for _, cell := range strings.Split(row, ",") {
+        digits := 0
+        for digits < len(cell) && cell[digits] >= '0' && cell[digits] <= '9' {
+            digits++
+        }
+
+        number := 1
+        if digits > 0 {
+            number, _ = strconv.Atoi(cell[:digits])
+        }
+        code := cell[digits:]
+
+        if code == "" && digits > 0 {
+            x += number
+        } else if code == "x" {
+            for i := 0; i < number; i++ {
+                location := b.getIndex(x, y)
+                if location == nil {
+                    return x, fmt.Errorf("invalid gfen row width")
+                }
+
+                b.disableLocation(location)
+                x++
+            }
+        } else if digits > 0 {
+            index := pieceIndexFromCode(code)
+            if index < 0 || number >= b.players {
+                return x, fmt.Errorf("invalid gfen piece %s", cell)
+            }
+
+            location := b.getIndex(x, y)
+            if location == nil {
+                return x, fmt.Errorf("invalid gfen row width")
+            }
+
+            b.setPiece(location, b.getAllPiece(number, index))
+            x++
+        } else {
+            return x, fmt.Errorf("invalid gfen cell %s", cell)
+        }
+    }
+
+    return x, nil
+}
+
+func parseGFENSquares(b *SimpleBoard, squares string) (*Point, *Point, error) {
+    if squares == "-" {
+        return nil, nil, nil
+    }
+
+    pair := strings.Split(squares, ":")
+    if len(pair) != 2 {
+        return nil, nil, fmt.Errorf("invalid gfen squares %s", squares)
+    }
+
+    first, err := parseSquare(b, pair[0])
+    if err != nil {
+        return nil, nil, err
+    }
+
+    second, err := parseSquare(b, pair[1])
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return first, second, nil
+}
+
+func pieceCode(index int) string {
+    return piece_types[index].code()
+}
+
+func (t *pieceType) code() string {
+    code := t.name + t.orientation
+    if t.moved {
+        code += "m"
+    }
+
+    return code
+}
+
+func pieceIndexFromCode(code string) int {
+    for index := 0; index < totalPieces(); index++ {
+        if pieceCode(index) == code {
+            return index
+        }
+    }
+
+    return -1
+}
+
+func (s *SimpleGame) GFEN() (string, error) {
+    return s.gfen(s.fullMove())
+}
+
+func (s *SimpleGame) gfen(fullMove int) (string, error) {
+    if s.p.eliminatedPieces != ELIMINATED_WALLS {
+        return "", fmt.Errorf("gfen only supports eliminated pieces as walls")
+    }
+
+    var builder strings.Builder
+
+    builder.WriteString(fmt.Sprintf("%dx%d %d ", s.b.x, s.b.y, s.p.getCurrent()))
+
+    for color := 0; color < s.b.players; color++ {
+        if color > 0 {
+            builder.WriteString(",")
+        }
+
+        if s.p.playersAlive[color] && !s.b.playersDisabled[color] {
+            builder.WriteString("1")
+        } else {
+            builder.WriteString("0")
+        }
+    }
+    builder.WriteString(" ")
+
+    for y := 0; y < s.b.y; y++ {
+        if y > 0 {
+            builder.WriteString("/")
+        }
+
+        cells := []string{}
+        empty := 0
+        disabled := 0
+        for x := 0; x < s.b.x; x++ {
+            if !s.b.disableds[y][x] && disabled > 0 {
+                cells = append(cells, fmt.Sprintf("%dx", disabled))
+                disabled = 0
+            }
+            if (s.b.disableds[y][x] || s.b.pieces[y][x] != nil) && empty > 0 {
+                cells = append(cells, strconv.Itoa(empty))
+                empty = 0
+            }
+
+            piece := s.b.pieces[y][x]
+            if s.b.disableds[y][x] {
+                disabled++
+            } else if piece == nil {
+                empty++
+            } else {
+                cells = append(cells, strconv.Itoa(piece.color) + pieceCode(piece.index))
+            }
+        }
+
+        if disabled > 0 {
+            cells = append(cells, fmt.Sprintf("%dx", disabled))
+        }
+        if empty > 0 {
+            cells = append(cells, strconv.Itoa(empty))
+        }
+
+        builder.WriteString(strings.Join(cells, ","))
+    }
+
+    enPassants := []string{}
+    vulnerables := []string{}
+    for color := 0; color < s.b.players; color++ {
+        target, risk := s.b.getEnPassant(color)
+        enPassants = append(enPassants, gfenSquares(s.b, target, risk))
+
+        start, end := s.b.getVulnerable(color)
+        vulnerables = append(vulnerables, gfenSquares(s.b, start, end))
+    }
+    builder.WriteString(" " + strings.Join(enPassants, ","))
+    builder.WriteString(" " + strings.Join(vulnerables, ","))
+
+    builder.WriteString(fmt.Sprintf(" %d %d", s.p.getHalfMoveClock(), fullMove))
+
+    return builder.String(), nil
+}
+
+func gfenSquares(b *SimpleBoard, first *Point, second *Point) string {
+    if first == nil || second == nil {
+        return "-"
+    }
+
+    return squareName(b, first) + ":" + squareName(b, second)
+}
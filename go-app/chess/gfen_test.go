@@ -0,0 +1,153 @@
+package chess
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_GFEN_Default(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    gfen, err := game.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(
+        t,
+        "8x8 0 1,1 1R,1N,1B,1Q,1KD,1B,1N,1R/1PD,1PD,1PD,1PD,1PD,1PD,1PD,1PD/8/8/8/8/0PU,0PU,0PU,0PU,0PU,0PU,0PU,0PU/0R,0N,0B,0Q,0KU,0B,0N,0R -,- -,- 0 1",
+        gfen,
+    )
+}
+
+func Test_GFEN_SmallFourPlayer(t *testing.T) {
+    game, err := NewSimpleSmallFourPlayerGame()
+    assert.Nil(t, err)
+
+    gfen, err := game.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(
+        t,
+        "8x8 0 1,1,1,1 1B,1PR,2,2KDm,2Rm,2N,2B/1N,1PR,2,2PD,2PD,2PD,2PD/1Rm,1PR,6/1KRm,1PR,6/6,3PL,3KLm/6,3PL,3Rm/0PU,0PU,0PU,0PU,2,3PL,3N/0B,0N,0Rm,0KUm,2,3PL,3B -,-,-,- -,-,-,- 0 1",
+        gfen,
+    )
+
+    gameFromGFEN, err := NewSimpleGameFromGFEN(gfen)
+    assert.Nil(t, err)
+    assert.Equal(t, game.Print(), gameFromGFEN.Print())
+}
+
+func Test_GFEN_FourPlayerRoundTrip(t *testing.T) {
+    game, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    err = game.Execute(3, 12, 3, 10, "") // white pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(1, 9, 3, 9, "") // red pawn advance
+    assert.Nil(t, err)
+
+    gfen, err := game.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(
+        t,
+        "14x14 2 1,1,1,1 3x,2R,2N,2B,2Q,2KD,2B,2N,2R,3x/3x,2PD,2PD,2PD,2PD,2PD,2PD,2PD,2PD,3x/3x,8,3x/1R,1PR,10,3PL,3R/1N,1PR,10,3PL,3N/1B,1PR,10,3PL,3B/1Q,1PR,10,3PL,3Q/1KR,1PR,10,3PL,3KL/1B,1PR,10,3PL,3B/1N,2,1PRm,8,3PL,3N/1R,1PR,1,0PUm,8,3PL,3R/3x,8,3x/3x,1,0PU,0PU,0PU,0PU,0PU,0PU,0PU,3x/3x,0R,0N,0B,0Q,0KU,0B,0N,0R,3x d3:d4,c5:d5,-,- -,-,-,- 0 1",
+        gfen,
+    )
+
+    gameFromGFEN, err := NewSimpleGameFromGFEN(gfen)
+    assert.Nil(t, err)
+    assert.Equal(t, game.Print(), gameFromGFEN.Print())
+
+    actual, err := gameFromGFEN.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(t, gfen, actual)
+
+    err = gameFromGFEN.Execute(10, 1, 10, 3, "") // black pawn advance
+    assert.Nil(t, err)
+    err = gameFromGFEN.Execute(12, 4, 10, 4, "") // blue pawn advance
+    assert.Nil(t, err)
+    err = gameFromGFEN.Execute(3, 10, 2, 9, "") // white capture en passant
+    assert.Nil(t, err)
+
+    actual, err = gameFromGFEN.GFEN()
+    assert.Nil(t, err)
+    assert.Contains(t, actual, "14x14 1 ")
+    assert.Contains(t, actual, " 0 2")
+}
+
+func Test_GFEN_Eliminated(t *testing.T) {
+    white := 0
+    red := 1
+
+    game, err := NewSimpleGameFromGFEN("4x4 0 1,0,1 0KUm,1KUm,2/4/1x,2,1x/2KUm,3 -,-,- -,-,- 0 1")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 2, len(state.Disabled))
+    assert.Equal(t, white, state.CurrentPlayer)
+
+    for _, piece := range state.Pieces {
+        assert.Equal(t, piece.C == red, piece.D)
+    }
+
+    err = game.Execute(0, 0, 1, 0, "") // white king captures disabled red king
+    assert.Nil(t, err)
+
+    gfen, err := game.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "4x4 2 1,0,1 1,0KUm,2/4/1x,2,1x/2KUm,3 -,-,- -,-,- 0 1", gfen)
+}
+
+func Test_GFEN_Invalid(t *testing.T) {
+    for _, gfen := range []string{
+        "",
+        "4x4 0 1,1 4/4/4/4 -,- -,- 0",
+        "4y4 0 1,1 4/4/4/4 -,- -,- 0 1",
+        "4x4 2 1,1 4/4/4/4 -,- -,- 0 1",
+        "4x4 0 1,2 4/4/4/4 -,- -,- 0 1",
+        "4x4 0 1,1 4/4/4 -,- -,- 0 1",
+        "4x4 0 1,1 4/4/4/5 -,- -,- 0 1",
+        "4x4 0 1,1 4/4/4/3,2KU -,- -,- 0 1",
+        "4x4 0 1,1 4/4/4/3,0K -,- -,- 0 1",
+        "4x4 0 1,1 4/4/4/3,KU -,- -,- 0 1",
+        "4x4 0 1,1 4/4/4/4 - -,- 0 1",
+        "4x4 0 1,1 4/4/4/4 a9:a1,- -,- 0 1",
+        "4x4 0 1,1 4/4/4/4 -,- -,- 0 0",
+    } {
+        _, err := NewSimpleGameFromGFEN(gfen)
+        assert.NotNil(t, err, gfen)
+    }
+}
+
+func Test_GFEN_EliminatedPieces(t *testing.T) {
+    red := 1
+
+    for _, eliminatedPieces := range []string{"walls", "remove", "random"} {
+        game := newVariantGame(t, `{
+            "Width": 4,
+            "Height": 4,
+            "Players": 3,
+            "EliminatedPieces": "` + eliminatedPieces + `",
+            "Pieces": [
+                {"X": 0, "Y": 0, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+                {"X": 3, "Y": 0, "Color": 1, "Type": "K", "Orientation": "U", "Moved": true},
+                {"X": 0, "Y": 3, "Color": 2, "Type": "K", "Orientation": "U", "Moved": true}
+            ]
+        }`)
+        err := game.Resign(red)
+        assert.Nil(t, err)
+
+        gfen, err := game.GFEN()
+        if eliminatedPieces != "walls" {
+            assert.NotNil(t, err, eliminatedPieces) // read back, the pieces would turn into walls
+            continue
+        }
+        assert.Nil(t, err)
+
+        imported, err := NewSimpleGameFromGFEN(gfen)
+        assert.Nil(t, err)
+        importedGFEN, err := imported.GFEN()
+        assert.Nil(t, err)
+        assert.Equal(t, gfen, importedGFEN)
+    }
+}
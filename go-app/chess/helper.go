@@ -291,6 +291,14 @@ type BoardData struct {
     Check bool
     Checkmate bool
     Stalemate bool
+    HalfMoveClock int // plies since the last capture or pawn move
+    DrawOffers []int // colors offering a draw
+    Teams []int // team of each color, empty when every color plays for itself
+    WinningTeam int // team of the winning player, -1 without teams or without a winner
+    Points []int // points of each color, empty without points
+    Ranking []int // colors from the most to the fewest points, empty without points
+    Reserves []map[string]int // pieces in hand of each color by letter, empty without crazyhouse
+    Checks [][]int // [checked][checker] checks received from each opponent, empty without three-check
 }
 
 type Command struct {
@@ -307,6 +315,11 @@ type PieceData struct {
     D bool // Disabled
 }
 
+type CaptureData struct {
+    T string // Type
+    C int // Color
+}
+
 type DisabledData struct {
     X int
     Y int
@@ -331,6 +344,7 @@ type MoveKey struct {
     XTo int
     YTo int
     Promotion string
+    Drop string // letter of the piece dropped from the reserve, XFrom and YFrom are -1
 }
 
 type MoveKeyWithScore struct {
@@ -339,6 +353,7 @@ type MoveKeyWithScore struct {
     XTo int
     YTo int
     Promotion string
+    Drop string
     Score int
 }
 
@@ -352,26 +367,48 @@ type Point struct {
     y int
 }
 
+func sign(n int) int {
+    if n > 0 {
+        return 1
+    } else if n < 0 {
+        return -1
+    }
+
+    return 0
+}
+
 
 
-type Array4[T any] struct {
+// the first four values are stored in place, atomic explosions spill the rest into a slice
+// the slice is behind a pointer to keep moves small, they are copied a lot while searching
+type ArrayVar[T any] struct {
     array [4]T
+    extra *[]T
     count int
 }
 
-func (a *Array4[T]) get() *T {
-    res := &a.array[a.count]
-    a.count += 1
-    return res
+func (a *ArrayVar[T]) at(i int) T {
+    if i < len(a.array) {
+        return a.array[i]
+    }
+    return (*a.extra)[i - len(a.array)]
 }
 
-func (a *Array4[T]) set(value T) {
-    a.array[a.count] = value
+func (a *ArrayVar[T]) set(value T) {
+    if a.count < len(a.array) {
+        a.array[a.count] = value
+    } else if a.extra == nil {
+        a.extra = &[]T{value}
+    } else {
+        *a.extra = append(*a.extra, value)
+    }
     a.count += 1
 }
 
-func (a *Array4[T]) clear() {
+// copies of the move keep the old extra values, so they are never reused
+func (a *ArrayVar[T]) clear() {
     a.count = 0
+    a.extra = nil
 }
 
 
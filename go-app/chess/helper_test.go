@@ -1,4 +1,18 @@
 package chess
 
-// TODO this file
+import (
+    "testing"
 
+    "github.com/stretchr/testify/assert"
+)
+
+// builds a game from a json variant definition
+func newVariantGame(t *testing.T, data string) Game {
+    definition, err := ParseVariant([]byte(data))
+    assert.Nil(t, err)
+
+    game, err := NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    return game
+}
@@ -0,0 +1,81 @@
+package chess
+
+/*
+Responsible for:
+- ending king of the hill games once a king reaches one of the hill squares
+*/
+
+// bonus for a king by its distance to the hill, so the bot races for it
+var hill_bonus = []int{200, 100, 50, 20, 10}
+
+// the center squares, 2x2 on boards with an even size like the 14x14 four player board
+func centerSquares(width int, height int) []VariantSquare {
+    squares := []VariantSquare{}
+    for y := (height - 1) / 2; y <= height / 2; y++ {
+        for x := (width - 1) / 2; x <= width / 2; x++ {
+            squares = append(squares, VariantSquare{x, y})
+        }
+    }
+
+    return squares
+}
+
+func (b *SimpleBoard) setHill(squares []VariantSquare) {
+    b.hill = []*Point{}
+    for _, square := range squares {
+        b.hill = append(b.hill, b.getIndex(square.X, square.Y))
+    }
+}
+
+func (b *SimpleBoard) kingOnHill(color int) bool {
+    king := b.kingLocations[color]
+    if king == nil {
+        return false
+    }
+
+    for _, location := range b.hill {
+        if location == king {
+            return true
+        }
+    }
+
+    return false
+}
+
+// king steps to the closest hill square, -1 without a king or a hill
+func (b *SimpleBoard) hillDistance(color int) int {
+    king := b.kingLocations[color]
+    distance := -1
+    if king == nil {
+        return distance
+    }
+
+    for _, location := range b.hill {
+        steps := max(abs(location.x - king.x), abs(location.y - king.y))
+        if distance < 0 || steps < distance {
+            distance = steps
+        }
+    }
+
+    return distance
+}
+
+// called after the move is executed and the moves are calculated, a king on the hill ends the game
+// with points the king scores the hill points and the player with the most points wins
+func (t *PlayerTransition) reachHill(b *SimpleBoard, color int) bool {
+    p := t.p
+    if b.hill == nil || !p.playersAlive[color] || !b.kingOnHill(color) {
+        return false
+    }
+
+    t.newCurrent = t.oldCurrent
+    t.newWinner = color
+    t.newGameOver = true
+
+    if p.pointsRules != nil {
+        t.pointsColor = color
+        t.points += p.pointsRules.Hill
+    }
+
+    return true
+}
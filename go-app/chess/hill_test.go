@@ -0,0 +1,175 @@
+package chess
+
+import (
+    "os"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_Hill_DefaultSquares(t *testing.T) {
+    for name, expected := range map[string][]Point{
+        "kingofthehill": {{3, 3}, {4, 3}, {3, 4}, {4, 4}},
+        "fourkingofthehill": {{6, 6}, {7, 6}, {6, 7}, {7, 7}},
+    } {
+        data, err := os.ReadFile("../variants/" + name + ".json")
+        assert.Nil(t, err)
+        game := newVariantGame(t, string(data))
+
+        hill := []Point{}
+        for _, location := range game.getBoard().hill {
+            hill = append(hill, *location)
+        }
+        assert.Equal(t, expected, hill, name)
+    }
+
+    definition, err := ParseVariant([]byte(`{"Width": 8, "Height": 8, "Players": 2, "KingOfTheHill": true, "Hill": [{"X": 8, "Y": 0}]}`))
+    assert.Nil(t, err)
+    assert.NotNil(t, definition.Validate())
+
+    // a disabled center square can't be part of the default hill
+    definition, err = ParseVariant([]byte(`{
+        "Width": 4,
+        "Height": 4,
+        "Players": 2,
+        "KingOfTheHill": true,
+        "Disabled": [{"X": 1, "Y": 1}],
+        "Pieces": [
+            {"X": 0, "Y": 3, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 3, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true}
+        ]
+    }`))
+    assert.Nil(t, err)
+    assert.NotNil(t, definition.Validate())
+    _, err = NewGameFromVariant(definition)
+    assert.NotNil(t, err)
+}
+
+func Test_Hill_Win(t *testing.T) {
+    white := 0
+
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "KingOfTheHill": true,
+        "Pieces": [
+            {"X": 4, "Y": 5, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 7, "Y": 0, "Color": 1, "Type": "R", "Moved": true}
+        ]
+    }`)
+
+    err := game.ExecuteSAN("Ke4")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, white, state.WinningPlayer)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.False(t, state.GameOver)
+}
+
+func Test_Hill_InsufficientMaterial(t *testing.T) {
+    white := 0
+
+    for _, pieces := range []string{
+        ``,
+        `, {"X": 1, "Y": 7, "Color": 0, "Type": "N"}`,
+        `, {"X": 2, "Y": 7, "Color": 0, "Type": "B"}`,
+    } {
+        game := newVariantGame(t, `{
+            "Width": 8,
+            "Height": 8,
+            "Players": 2,
+            "KingOfTheHill": true,
+            "Pieces": [
+                {"X": 4, "Y": 6, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+                {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true}` + pieces + `
+            ]
+        }`)
+        assert.False(t, game.getBoard().InsufficientMaterial(), pieces)
+
+        // the kings keep racing to the hill instead of drawing
+        err := game.ExecuteSAN("Ke3")
+        assert.Nil(t, err)
+        err = game.ExecuteSAN("Kb7")
+        assert.Nil(t, err)
+        err = game.ExecuteSAN("Ke4")
+        assert.Nil(t, err)
+
+        state, err := game.State()
+        assert.Nil(t, err)
+        assert.True(t, state.GameOver, pieces)
+        assert.Equal(t, white, state.WinningPlayer, pieces)
+    }
+}
+
+func Test_Hill_FourPlayerPoints(t *testing.T) {
+    red := 1
+
+    game := newVariantGame(t, `{
+        "Width": 6,
+        "Height": 6,
+        "Players": 4,
+        "KingOfTheHill": true,
+        "Points": {"Captures": {"P": 1}, "Hill": 20},
+        "Pieces": [
+            {"X": 5, "Y": 5, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 1, "Color": 0, "Type": "P", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 4, "Color": 1, "Type": "K", "Orientation": "R", "Moved": true},
+            {"X": 5, "Y": 0, "Color": 2, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 3, "Y": 0, "Color": 3, "Type": "K", "Orientation": "L", "Moved": true}
+        ]
+    }`)
+
+    err := game.Execute(5, 5, 4, 5, "")
+    assert.Nil(t, err)
+    err = game.Execute(0, 4, 1, 3, "")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.False(t, state.GameOver)
+
+    err = game.Execute(5, 0, 5, 1, "")
+    assert.Nil(t, err)
+    err = game.Execute(3, 0, 2, 1, "")
+    assert.Nil(t, err)
+    err = game.Execute(4, 5, 4, 4, "")
+    assert.Nil(t, err)
+    err = game.Execute(1, 3, 2, 3, "") // red reaches the hill first
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, []int{0, 20, 0, 0}, state.Points)
+    assert.Equal(t, red, state.WinningPlayer)
+}
+
+func Test_Hill_Searcher(t *testing.T) {
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "KingOfTheHill": true,
+        "Pieces": [
+            {"X": 4, "Y": 6, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true}
+        ]
+    }`)
+    b := game.getBoard()
+
+    stop := make(chan bool)
+    searcher := newParallelSearcher(b, game.getPlayerCollection(), stop)
+    moveKey, err := searcher.searchWithMinimax(3)
+    assert.Nil(t, err)
+    assert.Equal(t, 5, moveKey.YTo) // one step closer to the hill
+    assert.True(t, moveKey.XTo >= 3 && moveKey.XTo <= 5)
+}
@@ -22,6 +22,7 @@ type Invoker interface {
     executeHalf(p PlayerTransition) error
 	undo() error
 	redo() error
+    getHistory() []Command
     Copy() (Invoker, error)
 }
 
@@ -50,21 +51,20 @@ func (s *SimpleInvoker) executeHalf(p PlayerTransition) error {
 }
 
 func (s *SimpleInvoker) undo() error {
-	if s.index < 0 {
+    // refuse before reverting anything when only halves, for example a resign, are left
+    index := s.index
+    for index >= 0 && !s.history[index].fullMove {
+        index--
+    }
+	if index < 0 {
 		return fmt.Errorf("no moves to undo")
 	}
-    commandToUndo := s.history[s.index]
 
-    for !commandToUndo.fullMove {
+    for s.index > index {
         err := s.undoHelper()
         if err != nil {
             return err
         }
-
-        if s.index < 0 {
-            return fmt.Errorf("no moves to undo")
-        }
-        commandToUndo = s.history[s.index]
     }
 
     err := s.undoHelper()
@@ -137,6 +137,10 @@ func (s *SimpleInvoker) redoHelper() error {
 	return nil
 }
 
+func (s *SimpleInvoker) getHistory() []Command {
+    return s.history[:s.index+1]
+}
+
 func (s *SimpleInvoker) Copy() (Invoker, error) {
 	return &SimpleInvoker{
 		history: []Command{},
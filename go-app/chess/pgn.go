@@ -0,0 +1,418 @@
+package chess
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+/*
+Responsible for:
+- reading and writing the game history of two player games in Portable Game Notation
+*/
+const PGN_LINE_LENGTH = 80
+
+var pgn_seven_tag_roster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+type pgnRecord struct {
+    tags map[string]string
+    moves []string
+    result string
+}
+
+func NewSimpleGameFromPGN(pgn string) (Game, error) {
+    games, err := NewSimpleGamesFromPGN(pgn)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(games) == 0 {
+        return nil, fmt.Errorf("no pgn games")
+    }
+
+    return games[0], nil
+}
+
+func NewSimpleGamesFromPGN(pgn string) ([]Game, error) {
+    records, err := parsePGN(pgn)
+    if err != nil {
+        return nil, err
+    }
+
+    games := []Game{}
+    for _, record := range records {
+        game, err := createSimpleGameFromPGNRecord(record)
+        if err != nil {
+            return nil, err
+        }
+
+        games = append(games, game)
+    }
+
+    return games, nil
+}
+
+func createSimpleGameFromPGNRecord(record pgnRecord) (*SimpleGame, error) {
+    var b *SimpleBoard
+    var p *SimplePlayerCollection
+    var err error
+    fullMove := 1
+
+    if fen, ok := record.tags["FEN"]; ok {
+        b, p, fullMove, err = createSimpleBoardFromFEN(fen)
+    } else {
+        b, err = createSimpleBoardWithDefaultPieceLocations()
+        if err == nil {
+            p, err = createSimplePlayerCollectionWithDefaultPlayers()
+        }
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    i, err := invokerFactoryInstance.newSimpleInvoker()
+    if err != nil {
+        return nil, err
+    }
+
+    tags := map[string]string{}
+    for name, value := range record.tags {
+        if name != "Result" && name != "SetUp" && name != "FEN" {
+            tags[name] = value
+        }
+    }
+
+    s := &SimpleGame{
+        b: b,
+        p: p,
+        i: i,
+        fullMoveStart: fullMove,
+        tags: tags,
+    }
+
+    for ply, san := range record.moves {
+        err := s.ExecuteSAN(san)
+        if err != nil {
+            return nil, fmt.Errorf("invalid pgn move %s at ply %d: %v", san, ply + 1, err)
+        }
+    }
+
+    // games ended by resignation or agreement keep their result
+    result := record.result
+    if result == "" {
+        result = record.tags["Result"]
+    }
+    if winner, ok := pgnWinner(result); ok && !s.p.getGameOver() {
+        err := s.End(winner)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return s, nil
+}
+
+// comments, annotation glyphs, and variations are skipped
+func parsePGN(pgn string) ([]pgnRecord, error) {
+    records := []pgnRecord{}
+    record := pgnRecord{tags: map[string]string{}, moves: []string{}}
+    depth := 0
+
+    for i := 0; i < len(pgn); {
+        c := pgn[i]
+
+        if c == ';' || (c == '%' && (i == 0 || pgn[i-1] == '\n')) {
+            for i < len(pgn) && pgn[i] != '\n' {
+                i++
+            }
+        } else if c == '{' {
+            end := strings.IndexByte(pgn[i:], '}')
+            if end < 0 {
+                return nil, fmt.Errorf("unterminated pgn comment")
+            }
+            i += end + 1
+        } else if c == '(' {
+            depth++
+            i++
+        } else if c == ')' {
+            if depth == 0 {
+                return nil, fmt.Errorf("unmatched pgn variation")
+            }
+            depth--
+            i++
+        } else if c == '[' && depth == 0 {
+            if len(record.moves) > 0 {
+                records = append(records, record)
+                record = pgnRecord{tags: map[string]string{}, moves: []string{}}
+            }
+
+            name, value, end, err := parsePGNTag(pgn, i)
+            if err != nil {
+                return nil, err
+            }
+            record.tags[name] = value
+            i = end
+        } else if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+            i++
+        } else {
+            start := i
+            for i < len(pgn) && !strings.ContainsRune(" \t\n\r{}();[", rune(pgn[i])) {
+                i++
+            }
+            token := pgn[start:i]
+
+            if depth > 0 || token[0] == '$' {
+                continue
+            }
+
+            if isPGNResult(token) {
+                record.result = token
+                records = append(records, record)
+                record = pgnRecord{tags: map[string]string{}, moves: []string{}}
+                continue
+            }
+
+            // move numbers may be attached to the move, for example 1.e4
+            digits := len(token) - len(strings.TrimLeft(token, "0123456789"))
+            if digits == len(token) || token[digits] == '.' {
+                token = strings.TrimLeft(token[digits:], ".")
+            }
+
+            if token != "" && strings.Trim(token, "!?") != "" {
+                record.moves = append(record.moves, token)
+            }
+        }
+    }
+
+    if depth > 0 {
+        return nil, fmt.Errorf("unterminated pgn variation")
+    }
+
+    if len(record.tags) > 0 || len(record.moves) > 0 {
+        records = append(records, record)
+    }
+
+    return records, nil
+}
+
+func parsePGNTag(pgn string, i int) (string, string, int, error) {
+    end := i + 1
+    for end < len(pgn) && pgn[end] != ']' {
+        if pgn[end] == '"' {
+            end++
+            for end < len(pgn) && pgn[end] != '"' {
+                if pgn[end] == '\\' {
+                    end++
+                }
+                end++
+            }
+        }
+        end++
+    }
+    if end >= len(pgn) {
+        return "", "", end, fmt.Errorf("unterminated pgn tag")
+    }
+
+    tag := strings.TrimSpace(pgn[i+1:end])
+    space := strings.IndexAny(tag, " \t")
+    if space < 0 {
+        return "", "", end, fmt.Errorf("invalid pgn tag %s", tag)
+    }
+
+    name := tag[:space]
+    value := strings.TrimSpace(tag[space:])
+    if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+        return "", "", end, fmt.Errorf("invalid pgn tag %s", tag)
+    }
+
+    var builder strings.Builder
+    for j := 1; j < len(value) - 1; j++ {
+        if value[j] == '\\' && j + 1 < len(value) - 1 {
+            j++
+        }
+        builder.WriteByte(value[j])
+    }
+
+    return name, builder.String(), end + 1, nil
+}
+
+func (s *SimpleGame) PGN() (string, error) {
+    if s.b.players != 2 {
+        return "", fmt.Errorf("pgn requires two players")
+    }
+
+    startFullMove := max(s.fullMoveStart, 1)
+    startFEN, err := s.fen(startFullMove)
+    if err != nil {
+        return "", err
+    }
+
+    tokens := []string{}
+    number := startFullMove
+    previous := -1
+
+    err = s.replay(func(index int, command *Command) error {
+        if index == 0 {
+            fen, err := s.fen(startFullMove)
+            if err != nil {
+                return err
+            }
+            startFEN = fen
+        }
+
+        if !command.fullMove {
+            return nil
+        }
+
+        position := s.p.turnPosition(command.m.color)
+        if previous >= position {
+            number++
+        }
+
+        if position == 0 {
+            tokens = append(tokens, fmt.Sprintf("%d.", number))
+        } else if previous < 0 {
+            tokens = append(tokens, fmt.Sprintf("%d...", number))
+        }
+        previous = position
+
+        san, err := moveSAN(s.b, s.p, &command.m)
+        if err != nil {
+            return err
+        }
+        tokens = append(tokens, san)
+
+        return nil
+    })
+    if err != nil {
+        return "", err
+    }
+
+    result := pgnResult(s.p)
+    tokens = append(tokens, result)
+
+    var builder strings.Builder
+
+    for _, name := range pgn_seven_tag_roster {
+        value, ok := s.tags[name]
+        if name == "Result" {
+            value = result
+        } else if !ok && name == "Date" {
+            value = "????.??.??"
+        } else if !ok {
+            value = "?"
+        }
+
+        writePGNTag(&builder, name, value)
+    }
+
+    if startFEN != DEFAULT_FEN {
+        writePGNTag(&builder, "SetUp", "1")
+        writePGNTag(&builder, "FEN", startFEN)
+    }
+
+    names := []string{}
+    for name := range s.tags {
+        if !isPGNRosterTag(name) {
+            names = append(names, name)
+        }
+    }
+    sort.Strings(names)
+
+    for _, name := range names {
+        writePGNTag(&builder, name, s.tags[name])
+    }
+
+    builder.WriteString("\n")
+    writePGNMovetext(&builder, tokens)
+
+    return builder.String(), nil
+}
+
+// results have a score for each player, for example 1-0 or 0-1/2-1/2-0
+func isPGNResult(token string) bool {
+    if token == "*" {
+        return true
+    }
+
+    scores := strings.Split(token, "-")
+    if len(scores) < 2 {
+        return false
+    }
+
+    // castling written with zeros isn't a result
+    winner := false
+    for _, score := range scores {
+        if score != "0" && score != "1" && score != "1/2" {
+            return false
+        }
+        winner = winner || score != "0"
+    }
+
+    return winner
+}
+
+func isPGNRosterTag(name string) bool {
+    for _, rosterName := range pgn_seven_tag_roster {
+        if name == rosterName {
+            return true
+        }
+    }
+
+    return false
+}
+
+func pgnResult(p *SimplePlayerCollection) string {
+    if !p.getGameOver() {
+        return "*"
+    }
+
+    switch p.getWinner() {
+    case 0:
+        return "1-0"
+    case 1:
+        return "0-1"
+    }
+
+    return "1/2-1/2"
+}
+
+// the winner of a two player result, -1 for a draw
+func pgnWinner(result string) (int, bool) {
+    switch result {
+    case "1-0":
+        return 0, true
+    case "0-1":
+        return 1, true
+    case "1/2-1/2":
+        return -1, true
+    }
+
+    return 0, false
+}
+
+func writePGNTag(builder *strings.Builder, name string, value string) {
+    value = strings.ReplaceAll(value, "\\", "\\\\")
+    value = strings.ReplaceAll(value, "\"", "\\\"")
+
+    builder.WriteString(fmt.Sprintf("[%s \"%s\"]\n", name, value))
+}
+
+func writePGNMovetext(builder *strings.Builder, tokens []string) {
+    lineLength := 0
+
+    for _, token := range tokens {
+        if lineLength > 0 && lineLength + 1 + len(token) > PGN_LINE_LENGTH {
+            builder.WriteString("\n")
+            lineLength = 0
+        } else if lineLength > 0 {
+            builder.WriteString(" ")
+            lineLength++
+        }
+
+        builder.WriteString(token)
+        lineLength += len(token)
+    }
+
+    builder.WriteString("\n")
+}
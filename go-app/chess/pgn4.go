@@ -0,0 +1,291 @@
+package chess
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+/*
+Responsible for:
+- reading and writing the game history of four player games in a PGN4 style notation
+
+Colors are named Red, Blue, Yellow, and Green in turn order, starting from the bottom of the board.
+Each round of moves is written on its own line with the moves separated by two dots.
+Moves are written in long algebraic notation, for example Qg1xk5+ or h2-h3.
+A player eliminated by checkmate is marked with # in place of their move, a player who resigned with R.
+A player who resigned while it wasn't their turn is marked with R and their color, for example R:Yellow.
+The result has a score for each player, for example 0-1-0-0.
+Positions other than the default four player board are written in a GFEN tag.
+*/
+var pgn4_player_names = []string{"Red", "Blue", "Yellow", "Green"}
+
+func NewSimpleGameFromPGN4(pgn4 string) (Game, error) {
+    games, err := NewSimpleGamesFromPGN4(pgn4)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(games) == 0 {
+        return nil, fmt.Errorf("no pgn4 games")
+    }
+
+    return games[0], nil
+}
+
+func NewSimpleGamesFromPGN4(pgn4 string) ([]Game, error) {
+    records, err := parsePGN(pgn4)
+    if err != nil {
+        return nil, err
+    }
+
+    games := []Game{}
+    for _, record := range records {
+        game, err := createSimpleGameFromPGN4Record(record)
+        if err != nil {
+            return nil, err
+        }
+
+        games = append(games, game)
+    }
+
+    return games, nil
+}
+
+func createSimpleGameFromPGN4Record(record pgnRecord) (*SimpleGame, error) {
+    var b *SimpleBoard
+    var p *SimplePlayerCollection
+    var err error
+    fullMove := 1
+
+    if gfen, ok := record.tags["GFEN"]; ok {
+        b, p, fullMove, err = createSimpleBoardFromGFEN(gfen)
+    } else {
+        b, err = createSimpleFourPlayerBoardWithDefaultPieceLocations()
+        if err == nil {
+            p, err = createSimpleFourPlayerPlayerCollectionWithDefaultPlayers()
+        }
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if b.players != len(pgn4_player_names) {
+        return nil, fmt.Errorf("pgn4 requires four players")
+    }
+
+    i, err := invokerFactoryInstance.newSimpleInvoker()
+    if err != nil {
+        return nil, err
+    }
+
+    tags := map[string]string{}
+    for name, value := range record.tags {
+        if name != "Result" && name != "GFEN" {
+            tags[name] = value
+        }
+    }
+
+    s := &SimpleGame{
+        b: b,
+        p: p,
+        i: i,
+        fullMoveStart: fullMove,
+        tags: tags,
+    }
+
+    for ply, lan := range record.moves {
+        // eliminations happen on their own when the player is checkmated
+        if lan == "#" {
+            continue
+        }
+
+        if strings.HasPrefix(lan, "R:") || lan == "R" {
+            color := s.p.getCurrent()
+            if lan != "R" {
+                color = pgn4PlayerColor(strings.TrimPrefix(lan, "R:"))
+            }
+
+            err = s.Resign(color)
+            if err != nil {
+                return nil, fmt.Errorf("invalid pgn4 resignation at ply %d: %v", ply + 1, err)
+            }
+
+            continue
+        }
+
+        if s.p.getGameOver() {
+            return nil, fmt.Errorf("invalid pgn4 move %s at ply %d: game is over", lan, ply + 1)
+        }
+
+        move, err := lanToMove(s.b, s.p.getCurrent(), lan)
+        if err != nil {
+            return nil, fmt.Errorf("invalid pgn4 move %s at ply %d: %v", lan, ply + 1, err)
+        }
+
+        err = s.executeMove(*move)
+        if err != nil {
+            return nil, fmt.Errorf("invalid pgn4 move %s at ply %d: %v", lan, ply + 1, err)
+        }
+    }
+
+    return s, nil
+}
+
+func (s *SimpleGame) PGN4() (string, error) {
+    if s.b.players != len(pgn4_player_names) {
+        return "", fmt.Errorf("pgn4 requires four players")
+    }
+
+    defaultGame, err := NewSimpleFourPlayerGame()
+    if err != nil {
+        return "", err
+    }
+
+    defaultGFEN, err := defaultGame.GFEN()
+    if err != nil {
+        return "", err
+    }
+
+    startFullMove := max(s.fullMoveStart, 1)
+    startGFEN, err := s.gfen(startFullMove)
+    if err != nil {
+        return "", err
+    }
+
+    rounds := [][]string{}
+    previous := -1
+
+    err = s.replay(func(index int, command *Command) error {
+        if index == 0 {
+            gfen, err := s.gfen(startFullMove)
+            if err != nil {
+                return err
+            }
+            startGFEN = gfen
+        }
+
+        if !command.fullMove && !command.p.eliminated {
+            return nil
+        }
+
+        // resigning while it isn't your turn doesn't take a place in the round
+        if command.p.resigned && command.p.eliminatedColor != command.p.oldCurrent {
+            if len(rounds) == 0 {
+                rounds = append(rounds, []string{})
+            }
+            rounds[len(rounds)-1] = append(rounds[len(rounds)-1], "R:" + pgn4_player_names[command.p.eliminatedColor])
+
+            return nil
+        }
+
+        position := s.p.turnPosition(command.p.oldCurrent)
+        if previous >= position || len(rounds) == 0 {
+            rounds = append(rounds, []string{})
+        }
+        previous = position
+
+        token := "#"
+        if command.p.resigned {
+            token = "R"
+        } else if command.fullMove {
+            token = moveLAN(s.b, s.p, &command.m)
+        }
+        rounds[len(rounds)-1] = append(rounds[len(rounds)-1], token)
+
+        return nil
+    })
+    if err != nil {
+        return "", err
+    }
+
+    result := pgn4Result(s.p)
+
+    var builder strings.Builder
+
+    for _, name := range []string{"Event", "Site", "Date", "Round"} {
+        value, ok := s.tags[name]
+        if !ok && name == "Date" {
+            value = "????.??.??"
+        } else if !ok {
+            value = "?"
+        }
+
+        writePGNTag(&builder, name, value)
+    }
+
+    for _, name := range pgn4_player_names {
+        value, ok := s.tags[name]
+        if !ok {
+            value = "?"
+        }
+
+        writePGNTag(&builder, name, value)
+    }
+
+    writePGNTag(&builder, "Result", result)
+
+    if startGFEN != defaultGFEN {
+        writePGNTag(&builder, "GFEN", startGFEN)
+    }
+
+    names := []string{}
+    for name := range s.tags {
+        if !isPGNRosterTag(name) && !isPGN4PlayerTag(name) {
+            names = append(names, name)
+        }
+    }
+    sort.Strings(names)
+
+    for _, name := range names {
+        writePGNTag(&builder, name, s.tags[name])
+    }
+
+    builder.WriteString("\n")
+
+    for i, round := range rounds {
+        builder.WriteString(fmt.Sprintf("%d. %s\n", startFullMove + i, strings.Join(round, " .. ")))
+    }
+    builder.WriteString(result + "\n")
+
+    return builder.String(), nil
+}
+
+func isPGN4PlayerTag(name string) bool {
+    for _, playerName := range pgn4_player_names {
+        if name == playerName {
+            return true
+        }
+    }
+
+    return false
+}
+
+func pgn4PlayerColor(name string) int {
+    for color, playerName := range pgn4_player_names {
+        if name == playerName {
+            return color
+        }
+    }
+
+    return -1
+}
+
+func pgn4Result(p *SimplePlayerCollection) string {
+    if !p.getGameOver() {
+        return "*"
+    }
+
+    scores := []string{}
+    for color := 0; color < p.getPlayers(); color++ {
+        if p.getWinner() >= 0 && p.allied(p.getWinner(), color) {
+            scores = append(scores, "1")
+        } else if p.getWinner() < 0 && p.playersAlive[color] {
+            scores = append(scores, "1/2")
+        } else {
+            scores = append(scores, "0")
+        }
+    }
+
+    return strings.Join(scores, "-")
+}
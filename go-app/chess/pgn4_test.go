@@ -0,0 +1,152 @@
+package chess
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_PGN4_Default(t *testing.T) {
+    game, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    err = game.Execute(7, 12, 7, 11, "") // red pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(1, 7, 2, 7, "") // blue pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(6, 1, 6, 3, "") // yellow pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(12, 6, 11, 6, "") // green pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(6, 13, 7, 12, "") // red queen advance
+    assert.Nil(t, err)
+
+    pgn4, err := game.PGN4()
+    assert.Nil(t, err)
+
+    expected := `
+[Event "?"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[Red "?"]
+[Blue "?"]
+[Yellow "?"]
+[Green "?"]
+[Result "*"]
+
+1. h2-h3 .. b7-c7 .. g13-g11 .. m8-l8
+2. Qg1-h2
+*
+`
+    assert.Equal(t, strings.Trim(expected, " \t\n") + "\n", pgn4)
+
+    gameFromPGN4, err := NewSimpleGameFromPGN4(pgn4)
+    assert.Nil(t, err)
+
+    expectedGFEN, err := game.GFEN()
+    assert.Nil(t, err)
+    actualGFEN, err := gameFromPGN4.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(t, expectedGFEN, actualGFEN)
+}
+
+func Test_PGN4_Elimination(t *testing.T) {
+    blue := 1
+
+    game, err := NewSimpleGameFromGFEN("8x8 0 1,1,1,1 1KRm,6,2KDm/8/2,0KUm,5/1,0Q,6/8/8/8/7,3KLm -,-,-,- -,-,-,- 0 1")
+    assert.Nil(t, err)
+
+    err = game.Execute(1, 3, 1, 1, "") // red queen checkmate
+    assert.Nil(t, err)
+    err = game.Execute(7, 0, 6, 0, "") // yellow king advance
+    assert.Nil(t, err)
+    err = game.Execute(7, 7, 6, 7, "") // green king advance
+    assert.Nil(t, err)
+    err = game.Execute(2, 2, 3, 2, "") // red king advance
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    for _, piece := range state.Pieces {
+        assert.Equal(t, piece.C == blue, piece.D)
+    }
+
+    pgn4, err := game.PGN4()
+    assert.Nil(t, err)
+
+    expected := `
+[Event "?"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[Red "?"]
+[Blue "?"]
+[Yellow "?"]
+[Green "?"]
+[Result "*"]
+[GFEN "8x8 0 1,1,1,1 1KRm,6,2KDm/8/2,0KUm,5/1,0Q,6/8/8/8/7,3KLm -,-,-,- -,-,-,- 0 1"]
+
+1. Qb5-b7# .. # .. Kh8-g8 .. Kh1-g1
+2. Kc6-d6
+*
+`
+    assert.Equal(t, strings.Trim(expected, " \t\n") + "\n", pgn4)
+
+    gameFromPGN4, err := NewSimpleGameFromPGN4(pgn4)
+    assert.Nil(t, err)
+
+    expectedGFEN, err := game.GFEN()
+    assert.Nil(t, err)
+    actualGFEN, err := gameFromPGN4.GFEN()
+    assert.Nil(t, err)
+    assert.Equal(t, expectedGFEN, actualGFEN)
+
+    err = gameFromPGN4.Undo()
+    assert.Nil(t, err)
+    err = gameFromPGN4.Undo()
+    assert.Nil(t, err)
+    err = gameFromPGN4.Undo()
+    assert.Nil(t, err)
+    err = gameFromPGN4.Undo()
+    assert.Nil(t, err)
+
+    state, err = gameFromPGN4.State()
+    assert.Nil(t, err)
+    for _, piece := range state.Pieces {
+        assert.False(t, piece.D)
+    }
+}
+
+func Test_PGN4_Result(t *testing.T) {
+    p, err := newSimplePlayerCollection(4)
+    assert.Nil(t, err)
+    assert.Equal(t, "*", pgn4Result(p))
+
+    p.eliminate(0)
+    p.setGameOver(true)
+    assert.Equal(t, "0-1/2-1/2-1/2", pgn4Result(p))
+
+    p.setWinner(2)
+    assert.Equal(t, "0-0-1-0", pgn4Result(p))
+
+    assert.True(t, isPGNResult("0-0-1-0"))
+    assert.True(t, isPGNResult("0-1/2-1/2-1/2"))
+    assert.False(t, isPGNResult("0-0-0"))
+}
+
+func Test_PGN4_Invalid(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    _, err = game.PGN4()
+    assert.NotNil(t, err)
+
+    _, err = NewSimpleGameFromPGN4("1. h2-h5 .. b7-c7 *")
+    assert.NotNil(t, err)
+    assert.Equal(t, "invalid pgn4 move h2-h5 at ply 1: illegal move", err.Error())
+
+    _, err = NewSimpleGameFromPGN4("[GFEN \"4x4 0 1,1 0KUm,3/4/4/3,1KDm -,- -,- 0 1\"]\n\n*")
+    assert.NotNil(t, err)
+}
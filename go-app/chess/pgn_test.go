@@ -0,0 +1,275 @@
+package chess
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_PGN_Empty(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+
+    expected := `
+[Event "?"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "?"]
+[Black "?"]
+[Result "*"]
+
+*
+`
+    assert.Equal(t, strings.Trim(expected, " \t\n") + "\n", pgn)
+}
+
+func Test_PGN_Checkmate(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 6, 4, 4, "") // white pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(4, 1, 4, 3, "") // black pawn advance
+    assert.Nil(t, err)
+    err = game.Execute(5, 7, 2, 4, "") // white bishop advance
+    assert.Nil(t, err)
+    err = game.Execute(1, 0, 2, 2, "") // black knight advance
+    assert.Nil(t, err)
+    err = game.Execute(3, 7, 7, 3, "") // white queen advance
+    assert.Nil(t, err)
+    err = game.Execute(6, 0, 5, 2, "") // black knight advance
+    assert.Nil(t, err)
+    err = game.Execute(7, 3, 5, 1, "") // white queen capture
+    assert.Nil(t, err)
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+
+    expected := `
+[Event "?"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "?"]
+[Black "?"]
+[Result "1-0"]
+
+1. e4 e5 2. Bc4 Nc6 3. Qh5 Nf6 4. Qxf7# 1-0
+`
+    assert.Equal(t, strings.Trim(expected, " \t\n") + "\n", pgn)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    pgn, err = game.PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn, "[Result \"*\"]")
+    assert.Contains(t, pgn, "1. e4 e5 2. Bc4 Nc6 3. Qh5 Nf6 *")
+}
+
+func Test_PGN_SetUp(t *testing.T) {
+    fen := "r3k2r/1P6/8/3pP3/8/8/8/R3K2R w KQkq d6 0 12"
+    game, err := NewSimpleGameFromFEN(fen)
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 3, 3, 2, "") // white pawn capture en passant
+    assert.Nil(t, err)
+    err = game.Execute(4, 0, 7, 0, "") // black king side castle
+    assert.Nil(t, err)
+    err = game.Execute(1, 1, 0, 0, "Q") // white pawn capture and promote
+    assert.Nil(t, err)
+    err = game.Execute(5, 0, 0, 0, "") // black rook capture
+    assert.Nil(t, err)
+    err = game.Execute(4, 7, 0, 7, "") // white queen side castle
+    assert.Nil(t, err)
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+
+    expected := `
+[Event "?"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "?"]
+[Black "?"]
+[Result "*"]
+[SetUp "1"]
+[FEN "r3k2r/1P6/8/3pP3/8/8/8/R3K2R w KQkq d6 0 12"]
+
+12. exd6 O-O 13. bxa8=Q+ Rxa8 14. O-O-O *
+`
+    assert.Equal(t, strings.Trim(expected, " \t\n") + "\n", pgn)
+
+    fen, err = game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "r5k1/8/3P4/8/8/8/8/2KR3R b - - 1 14", fen)
+}
+
+func Test_PGN_Disambiguation(t *testing.T) {
+    game, err := NewSimpleGameFromFEN("4k3/8/8/R7/8/8/4K3/R6R b - - 0 30")
+    assert.Nil(t, err)
+
+    err = game.Execute(4, 0, 3, 0, "") // black king advance
+    assert.Nil(t, err)
+    err = game.Execute(7, 7, 5, 7, "") // white rook advance
+    assert.Nil(t, err)
+    err = game.Execute(3, 0, 4, 0, "") // black king advance
+    assert.Nil(t, err)
+    err = game.Execute(0, 7, 0, 5, "") // white rook advance
+    assert.Nil(t, err)
+    err = game.Execute(4, 0, 4, 1, "") // black king advance
+    assert.Nil(t, err)
+    err = game.Execute(0, 5, 0, 4, "") // white rook advance
+    assert.Nil(t, err)
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn, "30... Kd8 31. Rhf1 Ke8 32. R1a3 Ke7 33. R3a4 *")
+}
+
+func Test_PGN_FourPlayer(t *testing.T) {
+    game, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    _, err = game.PGN()
+    assert.NotNil(t, err)
+}
+
+func Test_PGN_Import(t *testing.T) {
+    pgn := `
+[Event "Paris"]
+[Site "Paris FRA"]
+[Date "1858.??.??"]
+[Round "?"]
+[White "Paul Morphy"]
+[Black "Duke Karl / Count Isouard"]
+[Result "1-0"]
+[ECO "C41"]
+
+1.e4 e5 2. Nf3 d6 {Philidor Defense} 3. d4 Bg4 $2 (3... exd4 4. Nxd4) 4. dxe5
+Bxf3 5. Qxf3 dxe5 6. Bc4 Nf6 7. Qb3 Qe7 8. Nc3 c6 9. Bg5 b5 10. Nxb5! cxb5
+11. Bxb5+ Nbd7 12. O-O-O Rd8 13. Rxd7 Rxd7 14. Rd1 Qe6 15. Bxd7+ Nxd7
+; the finish
+16. Qb8+!! Nxb8 17. Rd8# 1-0
+`
+    game, err := NewSimpleGameFromPGN(pgn)
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, 0, state.WinningPlayer)
+
+    actual, err := game.PGN()
+    assert.Nil(t, err)
+
+    expected := `
+[Event "Paris"]
+[Site "Paris FRA"]
+[Date "1858.??.??"]
+[Round "?"]
+[White "Paul Morphy"]
+[Black "Duke Karl / Count Isouard"]
+[Result "1-0"]
+[ECO "C41"]
+
+1. e4 e5 2. Nf3 d6 3. d4 Bg4 4. dxe5 Bxf3 5. Qxf3 dxe5 6. Bc4 Nf6 7. Qb3 Qe7 8.
+Nc3 c6 9. Bg5 b5 10. Nxb5 cxb5 11. Bxb5+ Nbd7 12. O-O-O Rd8 13. Rxd7 Rxd7 14.
+Rd1 Qe6 15. Bxd7+ Nxd7 16. Qb8+ Nxb8 17. Rd8# 1-0
+`
+    assert.Equal(t, strings.Trim(expected, " \t\n") + "\n", actual)
+
+    for i := 0; i < 33; i++ {
+        err = game.Undo()
+        assert.Nil(t, err)
+    }
+
+    expectedGame, err := NewSimpleGame()
+    assert.Nil(t, err)
+    assert.Equal(t, expectedGame.Print(), game.Print())
+
+    err = game.Undo()
+    assert.NotNil(t, err)
+}
+
+func Test_PGN_ImportMultiple(t *testing.T) {
+    pgn := `
+[Event "First"]
+[FEN "4k3/8/8/8/8/8/8/R3K3 w Q - 0 1"]
+
+1. 0-0-0 Kf7 *
+
+[Event "Second"]
+
+1. d4 d5 2. c4 1/2-1/2
+`
+    games, err := NewSimpleGamesFromPGN(pgn)
+    assert.Nil(t, err)
+    assert.Equal(t, 2, len(games))
+
+    fen, err := games[0].FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "8/5k2/8/8/8/8/8/2KR4 w - - 2 2", fen)
+
+    fen, err = games[1].FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "rnbqkbnr/ppp1pppp/8/3p4/2PP4/8/PP2PPPP/RNBQKBNR b KQkq c3 0 2", fen)
+}
+
+func Test_PGN_ImportInvalid(t *testing.T) {
+    _, err := NewSimpleGameFromPGN("1. e4 e5 2. Ke3 *")
+    assert.NotNil(t, err)
+    assert.Equal(t, "invalid pgn move Ke3 at ply 3: illegal move", err.Error())
+
+    _, err = NewSimpleGameFromPGN("[FEN \"4k3/8/8/8/8/8/8/2N1K1N1 w - - 0 1\"]\n\n1. Ne2 *")
+    assert.NotNil(t, err)
+    assert.Equal(t, "invalid pgn move Ne2 at ply 1: ambiguous move", err.Error())
+
+    for _, pgn := range []string{
+        "",
+        "[Event \"?\" 1. e4 *",
+        "[Event] 1. e4 *",
+        "1. e4 {comment *",
+        "1. e4 (1. d4 *",
+        "1. e4 ) *",
+        "1. e9 *",
+    } {
+        _, err := NewSimpleGameFromPGN(pgn)
+        assert.NotNil(t, err, pgn)
+    }
+}
+
+func Test_PGN_ImportResign(t *testing.T) {
+    pgn := `
+[Event "Resigned"]
+[Result "0-1"]
+
+1. f3 e5 2. g4 0-1
+`
+    game, err := NewSimpleGameFromPGN(pgn)
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, 1, state.WinningPlayer)
+
+    actual, err := game.PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, actual, "[Result \"0-1\"]")
+    assert.True(t, strings.HasSuffix(actual, "2. g4 0-1\n"))
+
+    games, err := NewSimpleGamesFromPGN("1. d4 d5 2. c4 1/2-1/2")
+    assert.Nil(t, err)
+
+    actual, err = games[0].PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, actual, "[Result \"1/2-1/2\"]")
+    assert.True(t, strings.HasSuffix(actual, "2. c4 1/2-1/2\n"))
+}
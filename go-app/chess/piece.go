@@ -1,5 +1,13 @@
 package chess
 
+import (
+    "fmt"
+)
+
+/*
+Responsible for:
+- keeping track of the piece types, boards size their tables from the registry
+*/
 const (
     PAWN_R = 0
     PAWN_L = 1
@@ -22,103 +30,117 @@ const (
     KING_L_M = 18
     KING_D_M = 19
     KING_U_M = 20
-    TOTAL_PIECES = 21
 )
 
-var piece_moved_indexes = []int{
-    PAWN_R_M,
-    PAWN_L_M,
-    PAWN_D_M,
-    PAWN_U_M,
-    PAWN_R_M,
-    PAWN_L_M,
-    PAWN_D_M,
-    PAWN_U_M,
-    KNIGHT,
-    BISHOP,
-    ROOK_M,
-    ROOK_M,
-    QUEEN,
-    KING_R_M,
-    KING_L_M,
-    KING_D_M,
-    KING_U_M,
-    KING_R_M,
-    KING_L_M,
-    KING_D_M,
-    KING_U_M,
-}
-
-var piece_values = []int{
-    100,
-    100,
-    100,
-    100,
-    100,
-    100,
-    100,
-    100,
-    300,
-    300,
-    500,
-    500,
-    900,
-    500,
-    500,
-    500,
-    500,
-    500,
-    500,
-    500,
-    500,
-}
-
-var piece_names = []string{
-    "P",
-    "P",
-    "P",
-    "P",
-    "P",
-    "P",
-    "P",
-    "P",
-    "N",
-    "B",
-    "R",
-    "R",
-    "Q",
-    "K",
-    "K",
-    "K",
-    "K",
-    "K",
-    "K",
-    "K",
-    "K",
-}
-
-var piece_move_functions = []func(*SimpleBoard, *Piece, *Point) {
-    pawn_r_moves,
-    pawn_l_moves,
-    pawn_d_moves,
-    pawn_u_moves,
-    pawn_r_moves,
-    pawn_l_moves,
-    pawn_d_moves,
-    pawn_u_moves,
-    knight_moves,
-    bishop_moves,
-    rook_moves,
-    rook_moves,
-    queen_moves,
-    king_lr_moves,
-    king_lr_moves,
-    king_ud_moves,
-    king_ud_moves,
-    king_lr_moves,
-    king_lr_moves,
-    king_ud_moves,
-    king_ud_moves,
+type pieceType struct {
+    name string // letter used in fen, san, and pgn
+    value int
+    movedIndex int // the piece after it moved, -1 when moving doesn't change the piece
+    moves func(*SimpleBoard, *Piece, *Point)
+    orientation string // R, L, D, or U for pieces that depend on the direction they face
+    pawn bool
+    king bool
+    moved bool
+}
+
+var piece_types []pieceType
+
+// pawns promote to these pieces unless a variant picks others, a promoted rook can't castle
+var default_promotions = []int{QUEEN, ROOK_M, BISHOP, KNIGHT}
+
+// fairy pieces for capablanca, grand chess, and other large boards
+var (
+    ARCHBISHOP int
+    CHANCELLOR int
+    AMAZON int
+    CAMEL int
+    ZEBRA int
+    NIGHTRIDER int
+    GRASSHOPPER int
+)
+
+// the move functions refer back to the registry, so it is filled once the package is initialized
+func init() {
+    // the built in pieces are in the order of their constants
+    piece_types = []pieceType{
+        {"P", 100, PAWN_R_M, pawn_r_moves, "R", true, false, false},
+        {"P", 100, PAWN_L_M, pawn_l_moves, "L", true, false, false},
+        {"P", 100, PAWN_D_M, pawn_d_moves, "D", true, false, false},
+        {"P", 100, PAWN_U_M, pawn_u_moves, "U", true, false, false},
+        {"P", 100, PAWN_R_M, pawn_r_moves, "R", true, false, true},
+        {"P", 100, PAWN_L_M, pawn_l_moves, "L", true, false, true},
+        {"P", 100, PAWN_D_M, pawn_d_moves, "D", true, false, true},
+        {"P", 100, PAWN_U_M, pawn_u_moves, "U", true, false, true},
+        {"N", 300, KNIGHT, knight_moves, "", false, false, false},
+        {"B", 300, BISHOP, bishop_moves, "", false, false, false},
+        {"R", 500, ROOK_M, rook_moves, "", false, false, false},
+        {"R", 500, ROOK_M, rook_moves, "", false, false, true},
+        {"Q", 900, QUEEN, queen_moves, "", false, false, false},
+        {"K", 500, KING_R_M, king_lr_moves, "R", false, true, false},
+        {"K", 500, KING_L_M, king_lr_moves, "L", false, true, false},
+        {"K", 500, KING_D_M, king_ud_moves, "D", false, true, false},
+        {"K", 500, KING_U_M, king_ud_moves, "U", false, true, false},
+        {"K", 500, KING_R_M, king_lr_moves, "R", false, true, true},
+        {"K", 500, KING_L_M, king_lr_moves, "L", false, true, true},
+        {"K", 500, KING_D_M, king_ud_moves, "D", false, true, true},
+        {"K", 500, KING_U_M, king_ud_moves, "U", false, true, true},
+    }
+
+    ARCHBISHOP = mustRegisterBetzaPiece(pieceType{name: "A", value: 825}, "BN")
+    CHANCELLOR = mustRegisterBetzaPiece(pieceType{name: "C", value: 875}, "RN")
+    AMAZON = mustRegisterBetzaPiece(pieceType{name: "M", value: 1200}, "QN")
+    CAMEL = mustRegisterBetzaPiece(pieceType{name: "L", value: 250}, "C")
+    ZEBRA = mustRegisterBetzaPiece(pieceType{name: "Z", value: 250}, "Z")
+    NIGHTRIDER = mustRegisterBetzaPiece(pieceType{name: "H", value: 500}, "NN")
+    GRASSHOPPER = mustRegisterPiece(pieceType{name: "G", value: 200, movedIndex: -1, moves: grasshopper_moves})
+}
+
+// pieces have to be registered before any board is created, returns the index of the first new piece
+// pieces sharing a letter differ by their orientation or moved flag, so a piece and its moved version are registered together
+func registerPiece(types ...pieceType) (int, error) {
+    codes := map[string]bool{}
+    for _, t := range types {
+        code := t.code()
+        if codes[code] || pieceIndexFromCode(code) >= 0 {
+            return -1, fmt.Errorf("piece %s is already registered", code)
+        }
+        codes[code] = true
+    }
+
+    index := len(piece_types)
+    for i, t := range types {
+        if t.movedIndex < 0 {
+            t.movedIndex = index + i
+        }
+
+        piece_types = append(piece_types, t)
+    }
+
+    return index, nil
+}
+
+func mustRegisterPiece(t pieceType) int {
+    index, err := registerPiece(t)
+    if err != nil {
+        panic(err)
+    }
+
+    return index
+}
+
+func totalPieces() int {
+    return len(piece_types)
+}
+
+// the first piece with the letter, pieces with an orientation or moved flag share their letter
+func pieceIndexFromName(name string) int {
+    for index, t := range piece_types {
+        if t.name == name {
+            return index
+        }
+    }
+
+    return -1
 }
 
 var pawn_u_directions = []*Point{
@@ -153,6 +175,21 @@ var pawn_r_directions = []*Point{
     {1, 1},
 }
 
+func pawnDirections(index int) []*Point {
+    switch index {
+    case PAWN_U, PAWN_U_M:
+        return pawn_u_directions
+    case PAWN_D, PAWN_D_M:
+        return pawn_d_directions
+    case PAWN_L, PAWN_L_M:
+        return pawn_l_directions
+    case PAWN_R, PAWN_R_M:
+        return pawn_r_directions
+    }
+
+    return nil
+}
+
 var knight_directions = []*Point{
     {1, 2},
     {-1, 2},
@@ -213,40 +250,31 @@ type Piece struct {
 }
 
 func (p *Piece) value() int {
-    return piece_values[p.index]
+    return piece_types[p.index].value
 }
 
 func (p *Piece) print() string {
-    return piece_names[p.index]
+    return piece_types[p.index].name
 }
 
 func (p *Piece) movedIndex() int {
-    return piece_moved_indexes[p.index]
+    return piece_types[p.index].movedIndex
 }
 
 func (p *Piece) isKing() bool {
-    return p.index > QUEEN
+    return piece_types[p.index].king
 }
 
 func (p *Piece) isPawn() bool {
-    return p.index < KNIGHT
+    return piece_types[p.index].pawn
 }
 
 func (p *Piece) moved() bool {
-    if p.index > PAWN_U && p.index < KNIGHT {
-        return true
-    }
-    if p.index == ROOK_M {
-        return true
-    }
-    if p.index > KING_U {
-        return true
-    }
-    return false
+    return piece_types[p.index].moved
 }
 
 func (p *Piece) moves(b *SimpleBoard, fromLocation *Point) {
-    piece_move_functions[p.index](b, p, fromLocation)
+    piece_types[p.index].moves(b, p, fromLocation)
 }
 
 func addDirection(
@@ -267,7 +295,7 @@ func addDirection(
         currentPiece = b.getPiece(currentLocation)
         if currentPiece == nil { // no piece
             addMoveSimple(b, fromPiece, fromLocation, currentPiece, currentLocation, nil)
-        } else if currentPiece.color != fromPiece.color { // enemy piece
+        } else if !b.allied(currentPiece.color, fromPiece.color) { // enemy piece
             addMoveSimple(b, fromPiece, fromLocation, currentPiece, currentLocation, nil)
             break
         } else { // ally piece
@@ -277,6 +305,31 @@ func addDirection(
     }
 }
 
+// moves along the direction over the first piece and lands right behind it
+func addHop(
+    b *SimpleBoard,
+    fromPiece *Piece,
+    fromLocation *Point,
+    direction *Point,
+) {
+    hurdleLocation := b.addIndex(fromLocation, direction)
+    for hurdleLocation != nil && b.getPiece(hurdleLocation) == nil {
+        hurdleLocation = b.addIndex(hurdleLocation, direction)
+    }
+
+    toLocation := b.addIndex(hurdleLocation, direction)
+    if toLocation == nil {
+        return
+    }
+
+    toPiece := b.getPiece(toLocation)
+    if toPiece == nil || !b.allied(toPiece.color, fromPiece.color) {
+        addMoveSimple(b, fromPiece, fromLocation, toPiece, toLocation, nil)
+    } else {
+        addMoveAllyDefense(b, fromPiece, fromLocation, toLocation)
+    }
+}
+
 func addSimple(
 	b *SimpleBoard,
     fromPiece *Piece,
@@ -291,7 +344,7 @@ func addSimple(
 	toPiece := b.getPiece(toLocation)
 	if toPiece == nil { // no piece
         addMoveSimple(b, fromPiece, fromLocation, toPiece, toLocation, nil)
-	} else if toPiece.color != fromPiece.color { // enemy piece
+	} else if !b.allied(toPiece.color, fromPiece.color) { // enemy piece
         addMoveSimple(b, fromPiece, fromLocation, toPiece, toLocation, nil)
 	} else { // ally piece
         addMoveAllyDefense(b, fromPiece, fromLocation, toLocation)
@@ -328,17 +381,12 @@ func pawnAddForward(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, direc
     to3Location := b.addIndex(fromLocation, directions[2])
     piece1 := b.getPiece(to1Location)
     piece2 := b.getPiece(to2Location)
-    queen := b.getAllPiece(fromPiece.color, QUEEN)
-    rook_m := b.getAllPiece(fromPiece.color, ROOK_M)
-    bishop := b.getAllPiece(fromPiece.color, BISHOP)
-    knight := b.getAllPiece(fromPiece.color, KNIGHT)
 
     if piece1 == nil { // no piece on location
         if to2Location == nil { // location doesn't exist
-            addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, queen)
-            addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, rook_m)
-            addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, bishop)
-            addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, knight)
+            for _, index := range b.promotions {
+                addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, b.getAllPiece(fromPiece.color, index))
+            }
             return
         } else {
             addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, nil)
@@ -353,10 +401,9 @@ func pawnAddForward(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, direc
 
     if piece2 == nil { // no piece on location
         if to3Location == nil { // location doesn't exist
-            addMoveRevealEnPassant(b, fromPiece, fromLocation, piece2, to2Location, queen, to1Location, to2Location)
-            addMoveRevealEnPassant(b, fromPiece, fromLocation, piece2, to2Location, rook_m, to1Location, to2Location)
-            addMoveRevealEnPassant(b, fromPiece, fromLocation, piece2, to2Location, bishop, to1Location, to2Location)
-            addMoveRevealEnPassant(b, fromPiece, fromLocation, piece2, to2Location, knight, to1Location, to2Location)
+            for _, index := range b.promotions {
+                addMoveRevealEnPassant(b, fromPiece, fromLocation, piece2, to2Location, b.getAllPiece(fromPiece.color, index), to1Location, to2Location)
+            }
         } else {
             addMoveRevealEnPassant(b, fromPiece, fromLocation, piece2, to2Location, nil, to1Location, to2Location)
         }
@@ -370,27 +417,21 @@ func pawnAddCaptures(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, dire
     to4Location := b.addIndex(to2Location, directions[0])
     piece1 := b.getPiece(to1Location)
     piece2 := b.getPiece(to2Location)
-    queen := b.getAllPiece(fromPiece.color, QUEEN)
-    rook_m := b.getAllPiece(fromPiece.color, ROOK_M)
-    bishop := b.getAllPiece(fromPiece.color, BISHOP)
-    knight := b.getAllPiece(fromPiece.color, KNIGHT)
 
     if to1Location != nil {
         if r1, r2 := b.getEnPassantRisks(fromPiece.color, to1Location); r1 != nil { // if the square is an en passant target
             if to3Location == nil {
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece1, to1Location, queen, r1, r2)
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece1, to1Location, rook_m, r1, r2)
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece1, to1Location, bishop, r1, r2)
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece1, to1Location, knight, r1, r2)
+                for _, index := range b.promotions {
+                    addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece1, to1Location, b.getAllPiece(fromPiece.color, index), r1, r2)
+                }
             } else {
                 addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece1, to1Location, nil, r1, r2)
             }
-        } else if piece1 != nil && piece1.color != fromPiece.color { // if the square is occupied by an enemy piece
+        } else if piece1 != nil && !b.allied(piece1.color, fromPiece.color) { // if the square is occupied by an enemy piece
             if to3Location == nil {
-                addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, queen)
-                addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, rook_m)
-                addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, bishop)
-                addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, knight)
+                for _, index := range b.promotions {
+                    addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, b.getAllPiece(fromPiece.color, index))
+                }
             } else {
                 addMoveSimple(b, fromPiece, fromLocation, piece1, to1Location, nil)
             }
@@ -402,19 +443,17 @@ func pawnAddCaptures(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, dire
     if to2Location != nil {
         if r1, r2 := b.getEnPassantRisks(fromPiece.color, to2Location); r1 != nil { // if the square is an en passant target
             if to4Location == nil {
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece2, to2Location, queen, r1, r2)
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece2, to2Location, rook_m, r1, r2)
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece2, to2Location, bishop, r1, r2)
-                addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece2, to2Location, knight, r1, r2)
+                for _, index := range b.promotions {
+                    addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece2, to2Location, b.getAllPiece(fromPiece.color, index), r1, r2)
+                }
             } else {
                 addMoveCaptureEnPassant(b, fromPiece, fromLocation, piece2, to2Location, nil, r1, r2)
             }
-        } else if piece2 != nil && piece2.color != fromPiece.color { // if the square is occupied by an enemy piece
+        } else if piece2 != nil && !b.allied(piece2.color, fromPiece.color) { // if the square is occupied by an enemy piece
             if to4Location == nil {
-                addMoveSimple(b, fromPiece, fromLocation, piece2, to2Location, queen)
-                addMoveSimple(b, fromPiece, fromLocation, piece2, to2Location, rook_m)
-                addMoveSimple(b, fromPiece, fromLocation, piece2, to2Location, bishop)
-                addMoveSimple(b, fromPiece, fromLocation, piece2, to2Location, knight)
+                for _, index := range b.promotions {
+                    addMoveSimple(b, fromPiece, fromLocation, piece2, to2Location, b.getAllPiece(fromPiece.color, index))
+                }
             } else {
                 addMoveSimple(b, fromPiece, fromLocation, piece2, to2Location, nil)
             }
@@ -448,28 +487,26 @@ func queen_moves(b *SimpleBoard, fromPiece *Piece, fromLocation *Point) {
     }
 }
 
-var king_lr_moves = func(b *SimpleBoard, fromPiece *Piece, fromLocation *Point) {
+func grasshopper_moves(b *SimpleBoard, fromPiece *Piece, fromLocation *Point) {
     for _, direction := range queen_directions {
-        addSimple(b, fromPiece, fromLocation, direction)
-    }
-
-    if fromPiece.moved() {
-        return
+        addHop(b, fromPiece, fromLocation, direction)
     }
+}
 
-    if b.Check(fromPiece.color) {
-        return
+var king_lr_moves = func(b *SimpleBoard, fromPiece *Piece, fromLocation *Point) {
+    for _, direction := range queen_directions {
+        addSimple(b, fromPiece, fromLocation, direction)
     }
-
-    addCastle(b, fromPiece, fromLocation, king_lr_directions[0], king_lr_directions[2], king_lr_directions[4])
-    addCastle(b, fromPiece, fromLocation, king_lr_directions[1], king_lr_directions[3], king_lr_directions[5])
 }
 
 var king_ud_moves = func(b *SimpleBoard, fromPiece *Piece, fromLocation *Point) {
     for _, direction := range queen_directions {
         addSimple(b, fromPiece, fromLocation, direction)
     }
+}
 
+// called once the moves of every piece are known, so attacked squares can be found
+func addCastles(b *SimpleBoard, fromPiece *Piece, fromLocation *Point) {
     if fromPiece.moved() {
         return
     }
@@ -478,8 +515,13 @@ var king_ud_moves = func(b *SimpleBoard, fromPiece *Piece, fromLocation *Point)
         return
     }
 
-    addCastle(b, fromPiece, fromLocation, king_ud_directions[0], king_ud_directions[2], king_ud_directions[4])
-    addCastle(b, fromPiece, fromLocation, king_ud_directions[1], king_ud_directions[3], king_ud_directions[5])
+    directions := king_lr_directions
+    if isKingUD(fromPiece.index) {
+        directions = king_ud_directions
+    }
+
+    addCastle(b, fromPiece, fromLocation, directions[0], directions[2], directions[4])
+    addCastle(b, fromPiece, fromLocation, directions[1], directions[3], directions[5])
 }
 
 func addCastle(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, direction *Point, kingOffset *Point, rookOffset *Point) {
@@ -568,6 +610,14 @@ func addCastle(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, direction
         return
     }
 
+    // the king can't pass through an attacked square, in chess960 it can pass through more than the rook covers
+    step := &Point{sign(toLocation.x - fromLocation.x), sign(toLocation.y - fromLocation.y)}
+    for location := fromLocation; location != toLocation; location = b.addIndex(location, step) {
+        if b.attacked(location, fromPiece.color) {
+            return
+        }
+    }
+
     var minx int
     var maxx int
     var miny int
@@ -595,6 +645,10 @@ func addCastle(b *SimpleBoard, fromPiece *Piece, fromLocation *Point, direction
     }
     vulnerableStart := b.getIndex(minx, miny)
     vulnerableEnd := b.getIndex(maxx, maxy)
+    if toLocation == fromLocation { // the king stays in place in some chess960 castles
+        vulnerableStart = nil
+        vulnerableEnd = nil
+    }
 
     addMoveCastle(b, fromPiece, fromLocation, toLocation, rook, fromRookLocation, toRookLocation, vulnerableStart, vulnerableEnd)
 }
@@ -251,6 +251,223 @@ func Test_Queen_Moves(t *testing.T) {
     )
 }
 
+func Test_Archbishop_Moves(t *testing.T) {
+    white := 0
+
+    b, err := newSimpleBoard(5, 5, 2)
+    assert.Nil(t, err)
+
+    archbishop := b.getAllPiece(white, ARCHBISHOP)
+    b.setPiece(b.getIndex(2, 2), archbishop)
+
+    archbishop.moves(b, b.getIndex(2, 2))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 16,
+        &b.captureMoves[white], 0,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(0, 0),
+            b.getIndex(4, 4),
+            b.getIndex(0, 1),
+            b.getIndex(4, 3),
+        },
+    )
+}
+
+func Test_Chancellor_Moves(t *testing.T) {
+    white := 0
+
+    b, err := newSimpleBoard(5, 5, 2)
+    assert.Nil(t, err)
+
+    chancellor := b.getAllPiece(white, CHANCELLOR)
+    b.setPiece(b.getIndex(2, 2), chancellor)
+
+    chancellor.moves(b, b.getIndex(2, 2))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 16,
+        &b.captureMoves[white], 0,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(2, 0),
+            b.getIndex(4, 2),
+            b.getIndex(1, 0),
+            b.getIndex(3, 4),
+        },
+    )
+}
+
+func Test_Amazon_Moves(t *testing.T) {
+    white := 0
+
+    b, err := newSimpleBoard(5, 5, 2)
+    assert.Nil(t, err)
+
+    amazon := b.getAllPiece(white, AMAZON)
+    b.setPiece(b.getIndex(2, 2), amazon)
+
+    amazon.moves(b, b.getIndex(2, 2))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 24,
+        &b.captureMoves[white], 0,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(0, 0),
+            b.getIndex(2, 4),
+            b.getIndex(0, 3),
+        },
+    )
+}
+
+func Test_Camel_Moves(t *testing.T) {
+    white := 0
+    black := 1
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    camel := b.getAllPiece(white, CAMEL)
+    b.setPiece(b.getIndex(3, 3), camel)
+    b.setPiece(b.getIndex(4, 6), b.getAllPiece(black, KNIGHT))
+    b.setPiece(b.getIndex(3, 4), b.getAllPiece(black, KNIGHT)) // leapers can't be blocked
+
+    camel.moves(b, b.getIndex(3, 3))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 7,
+        &b.captureMoves[white], 1,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(4, 6),
+            b.getIndex(2, 6),
+            b.getIndex(6, 4),
+            b.getIndex(0, 4),
+            b.getIndex(4, 0),
+            b.getIndex(2, 0),
+            b.getIndex(6, 2),
+            b.getIndex(0, 2),
+        },
+    )
+}
+
+func Test_Zebra_Moves(t *testing.T) {
+    white := 0
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    zebra := b.getAllPiece(white, ZEBRA)
+    b.setPiece(b.getIndex(3, 3), zebra)
+    b.setPiece(b.getIndex(5, 6), b.getAllPiece(white, KNIGHT))
+
+    zebra.moves(b, b.getIndex(3, 3))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 7,
+        &b.captureMoves[white], 0,
+        &b.defenseMoves[white], 1,
+        []*Point{
+            b.getIndex(5, 6),
+            b.getIndex(1, 6),
+            b.getIndex(6, 5),
+            b.getIndex(0, 5),
+            b.getIndex(5, 0),
+            b.getIndex(1, 0),
+            b.getIndex(6, 1),
+            b.getIndex(0, 1),
+        },
+    )
+}
+
+func Test_Nightrider_Moves(t *testing.T) {
+    white := 0
+    black := 1
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    nightrider := b.getAllPiece(white, NIGHTRIDER)
+    b.setPiece(b.getIndex(0, 0), nightrider)
+    b.setPiece(b.getIndex(2, 4), b.getAllPiece(black, KNIGHT))
+
+    nightrider.moves(b, b.getIndex(0, 0))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 4,
+        &b.captureMoves[white], 1,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(1, 2),
+            b.getIndex(2, 4),
+            b.getIndex(2, 1),
+            b.getIndex(4, 2),
+            b.getIndex(6, 3),
+        },
+    )
+}
+
+func Test_Grasshopper_Moves(t *testing.T) {
+    white := 0
+    black := 1
+
+    b, err := newSimpleBoard(8, 8, 2)
+    assert.Nil(t, err)
+
+    grasshopper := b.getAllPiece(white, GRASSHOPPER)
+    b.setPiece(b.getIndex(0, 0), grasshopper)
+    b.setPiece(b.getIndex(0, 3), b.getAllPiece(white, KNIGHT))
+    b.setPiece(b.getIndex(3, 3), b.getAllPiece(black, KNIGHT))
+    b.setPiece(b.getIndex(4, 4), b.getAllPiece(black, KNIGHT))
+    b.setPiece(b.getIndex(7, 0), b.getAllPiece(black, KNIGHT)) // nothing to land on behind the hurdle
+
+    grasshopper.moves(b, b.getIndex(0, 0))
+
+    Assert_LengthAndToLocations(
+        t,
+        &b.moves[white], 1,
+        &b.captureMoves[white], 1,
+        &b.defenseMoves[white], 0,
+        []*Point{
+            b.getIndex(0, 4),
+            b.getIndex(4, 4),
+        },
+    )
+}
+
+func Test_PieceRegistry(t *testing.T) {
+    white := 0
+
+    b, err := newSimpleBoard(10, 8, 2)
+    assert.Nil(t, err)
+
+    assert.Equal(t, totalPieces(), len(b.allPieces[white]))
+    assert.Equal(t, totalPieces(), len(b.zobristPieces[white]))
+    assert.Equal(t, totalPieces(), len(b.pieceSquareTables))
+
+    for _, index := range []int{ARCHBISHOP, CHANCELLOR, AMAZON, CAMEL, ZEBRA, NIGHTRIDER, GRASSHOPPER} {
+        piece := b.getAllPiece(white, index)
+        assert.Equal(t, index, piece.movedIndex())
+        assert.False(t, piece.isKing())
+        assert.False(t, piece.isPawn())
+        assert.False(t, piece.moved())
+        assert.Equal(t, index, pieceIndexFromCode(piece.print()))
+    }
+
+    assert.Equal(t, "A", b.getAllPiece(white, ARCHBISHOP).print())
+    assert.Equal(t, 875, b.getAllPiece(white, CHANCELLOR).value())
+    assert.Equal(t, ROOK_M, b.getAllPiece(white, ROOK).movedIndex())
+    assert.Equal(t, "KUm", pieceCode(KING_U_M))
+}
+
 func Test_King_Moves_CanCastleAndUnmoved(t *testing.T) {
     white := 0
 
@@ -264,6 +481,7 @@ func Test_King_Moves_CanCastleAndUnmoved(t *testing.T) {
     b.setPiece(b.getIndex(4, 2), rook)
 
     king.moves(b, b.getIndex(2, 2))
+    addCastles(b, king, b.getIndex(2, 2))
 
     Assert_LengthAndToLocations(
         t,
@@ -298,6 +516,7 @@ func Test_King_Moves_CanCastleAndMoved(t *testing.T) {
     b.setPiece(b.getIndex(4, 2), rook)
 
     king.moves(b, b.getIndex(2, 2))
+    addCastles(b, king, b.getIndex(2, 2))
 
     Assert_LengthAndToLocations(
         t,
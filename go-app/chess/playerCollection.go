@@ -21,9 +21,13 @@ func newSimplePlayerCollection(numberOfPlayers int) (*SimplePlayerCollection, er
 
     zobristCurrentPlayer := make([]uint64, numberOfPlayers)
     zobristPlayerAlive := make([]uint64, numberOfPlayers)
+    zobristPoints := make([]uint64, numberOfPlayers)
+    checks := make([][]int, numberOfPlayers)
     for i := 0; i < numberOfPlayers; i++ {
         zobristCurrentPlayer[i] = rand.Uint64()
         zobristPlayerAlive[i] = rand.Uint64()
+        zobristPoints[i] = rand.Uint64()
+        checks[i] = make([]int, numberOfPlayers)
     }
 
 	return &SimplePlayerCollection{
@@ -31,22 +35,39 @@ func newSimplePlayerCollection(numberOfPlayers int) (*SimplePlayerCollection, er
         playersAlive: playersAlive,
         currentPlayer: 0,
         winningPlayer: -1,
+        points: make([]int, numberOfPlayers),
         gameOver: false,
+        checks: checks,
 
         zobristCurrentPlayer: zobristCurrentPlayer,
         zobristPlayerAlive: zobristPlayerAlive,
+        zobristPoints: zobristPoints,
 	}, nil
 }
 
 type SimplePlayerCollection struct {
     players int
     playersAlive []bool
+    turnOrder []int
+    teams []int // team of each color, nil when every color plays for itself
     currentPlayer int
     winningPlayer int
     gameOver bool
+    halfMoveClock int // plies since the last capture or pawn move
+    halfMoveLimit int // plies before the game is drawn, 0 means fifty moves for each player
+    drawOffers int // bit set of the colors offering a draw
+    pointsRules *PointsRules // nil when the last player standing wins
+    capturePoints []int // points for capturing each piece index
+    points []int
+    eliminatedPieces int // what happens to the pieces of eliminated players
+    zombiePoints int // points for capturing a piece of an eliminated player when they are zombies
+    checkLimit int // checks from one opponent that eliminate a player, 0 without three-check
+    checks [][]int // [checked][checker] checks received from each opponent
 
     zobristCurrentPlayer []uint64
     zobristPlayerAlive []uint64
+    zobristPoints []uint64
+    zobristChecks [][][]uint64 // [checked][checker][count], only with a check limit
 }
 
 func (s *SimplePlayerCollection) colorOutOfBounds(color int) bool {
@@ -101,6 +122,111 @@ func (s *SimplePlayerCollection) setGameOver(gameOver bool) {
     s.gameOver = gameOver
 }
 
+func (s *SimplePlayerCollection) getHalfMoveClock() int {
+    return s.halfMoveClock
+}
+
+func (s *SimplePlayerCollection) setHalfMoveClock(halfMoveClock int) {
+    s.halfMoveClock = halfMoveClock
+}
+
+func (s *SimplePlayerCollection) getHalfMoveLimit() int {
+    if s.halfMoveLimit <= 0 {
+        return 50 * s.players
+    }
+
+    return s.halfMoveLimit
+}
+
+func (s *SimplePlayerCollection) setHalfMoveLimit(halfMoveLimit int) {
+    s.halfMoveLimit = halfMoveLimit
+}
+
+func (s *SimplePlayerCollection) getDrawOffers() int {
+    return s.drawOffers
+}
+
+func (s *SimplePlayerCollection) setDrawOffers(drawOffers int) {
+    s.drawOffers = drawOffers
+}
+
+func (s *SimplePlayerCollection) drawOffered(color int) bool {
+    return s.drawOffers & (1 << color) != 0
+}
+
+func (s *SimplePlayerCollection) setPointsRules(pointsRules *PointsRules) {
+    s.pointsRules = pointsRules
+    s.capturePoints = pointsRules.capturePoints()
+}
+
+func (s *SimplePlayerCollection) getPoints(color int) int {
+    if s.colorOutOfBounds(color) {
+        return 0
+    }
+
+    return s.points[color]
+}
+
+func (s *SimplePlayerCollection) addPoints(color int, points int) {
+    if s.colorOutOfBounds(color) {
+        return
+    }
+
+    s.points[color] += points
+}
+
+// colors from the most to the fewest points, ties keep the order of the colors
+func (s *SimplePlayerCollection) ranking() []int {
+    ranking := []int{}
+    for color := 0; color < s.players; color++ {
+        position := len(ranking)
+        for position > 0 && s.points[ranking[position-1]] < s.points[color] {
+            position--
+        }
+
+        ranking = append(ranking, 0)
+        copy(ranking[position+1:], ranking[position:])
+        ranking[position] = color
+    }
+
+    return ranking
+}
+
+// with points the player with the most points wins once the game is over, a tie for the lead is a draw
+func (s *SimplePlayerCollection) pointsWinner(winner int) int {
+    if s.pointsRules == nil {
+        return winner
+    }
+
+    ranking := s.ranking()
+    if len(ranking) > 1 && s.points[ranking[0]] == s.points[ranking[1]] {
+        return -1
+    }
+
+    return ranking[0]
+}
+
+// once two players are left, a lead of the claim win threshold ends the game
+func (s *SimplePlayerCollection) claimWin() bool {
+    if s.pointsRules == nil || s.pointsRules.ClaimWin <= 0 {
+        return false
+    }
+
+    alive := []int{}
+    for color, playerAlive := range s.playersAlive {
+        if playerAlive {
+            alive = append(alive, color)
+        }
+    }
+
+    if len(alive) != 2 {
+        return false
+    }
+
+    lead := s.points[alive[0]] - s.points[alive[1]]
+    return lead >= s.pointsRules.ClaimWin || -lead >= s.pointsRules.ClaimWin
+}
+
 func (s *SimplePlayerCollection) getNextAndRemaining() (int, int) {
     currentPlayer := s.currentPlayer
     for {
@@ -125,7 +251,38 @@ func (s *SimplePlayerCollection) getNextAndRemaining() (int, int) {
     return currentPlayer, remaining
 }
 
+// eliminated players whose pieces move randomly still take their turns
+func (s *SimplePlayerCollection) getNextTurn() int {
+    currentPlayer := s.currentPlayer
+    for {
+        currentPlayer = s.incrementOnce(currentPlayer)
+
+        if s.playersAlive[currentPlayer] || s.movesRandomly(currentPlayer) {
+            break
+        }
+
+        if s.currentPlayer == currentPlayer {
+            break
+        }
+    }
+
+    return currentPlayer
+}
+
+func (s *SimplePlayerCollection) movesRandomly(color int) bool {
+    return s.eliminatedPieces == ELIMINATED_RANDOM && !s.colorOutOfBounds(color) && !s.playersAlive[color]
+}
+
+func (s *SimplePlayerCollection) setEliminatedPieces(eliminatedPieces int, zombiePoints int) {
+    s.eliminatedPieces = eliminatedPieces
+    s.zombiePoints = zombiePoints
+}
+
 func (s *SimplePlayerCollection) incrementOnce(start int) int {
+    if s.turnOrder != nil {
+        return s.turnOrder[(s.turnPosition(start) + 1) % s.players]
+    }
+
     end := (start + 1) % s.players
     if end < 0 {
         end = s.players - 1
@@ -133,6 +290,68 @@ func (s *SimplePlayerCollection) incrementOnce(start int) int {
     return end
 }
 
+// the turn order has to contain every color once
+func (s *SimplePlayerCollection) setTurnOrder(turnOrder []int) {
+    s.turnOrder = append([]int{}, turnOrder...)
+    s.currentPlayer = s.turnOrder[0]
+}
+
+// position of the color within a round, used to tell when a new round starts
+func (s *SimplePlayerCollection) turnPosition(color int) int {
+    if s.turnOrder == nil {
+        return color
+    }
+
+    for position, turnColor := range s.turnOrder {
+        if turnColor == color {
+            return position
+        }
+    }
+
+    return -1
+}
+
+// the teams have to contain every color
+func (s *SimplePlayerCollection) setTeams(teams []int) {
+    s.teams = append([]int{}, teams...)
+}
+
+func (s *SimplePlayerCollection) getTeam(color int) int {
+    if s.teams == nil || s.colorOutOfBounds(color) {
+        return -1
+    }
+
+    return s.teams[color]
+}
+
+func (s *SimplePlayerCollection) allied(color1 int, color2 int) bool {
+    return color1 == color2 || (s.teams != nil && s.teams[color1] == s.teams[color2])
+}
+
+// number of teams with a player alive without counting the given color, -1 counts every player
+func (s *SimplePlayerCollection) teamsRemaining(without int) int {
+    remaining := 0
+    for color, alive := range s.playersAlive {
+        if !alive || color == without {
+            continue
+        }
+
+        counted := false
+        for other := 0; other < color; other++ {
+            if s.playersAlive[other] && other != without && s.allied(color, other) {
+                counted = true
+                break
+            }
+        }
+
+        if !counted {
+            remaining++
+        }
+    }
+
+    return remaining
+}
+
 func (s *SimplePlayerCollection) getPlayers() int {
     return s.players
 }
@@ -146,9 +365,30 @@ func (s *SimplePlayerCollection) Copy() (*SimplePlayerCollection, error) {
     for color, alive := range s.playersAlive {
         simplePlayerCollection.playersAlive[color] = alive
     }
+    if s.turnOrder != nil {
+        simplePlayerCollection.turnOrder = append([]int{}, s.turnOrder...)
+    }
+    if s.teams != nil {
+        simplePlayerCollection.setTeams(s.teams)
+    }
     simplePlayerCollection.currentPlayer = s.currentPlayer
     simplePlayerCollection.winningPlayer = s.winningPlayer
     simplePlayerCollection.gameOver = s.gameOver
+    simplePlayerCollection.halfMoveClock = s.halfMoveClock
+    simplePlayerCollection.halfMoveLimit = s.halfMoveLimit
+    simplePlayerCollection.drawOffers = s.drawOffers
+    if s.pointsRules != nil {
+        simplePlayerCollection.setPointsRules(s.pointsRules)
+    }
+    copy(simplePlayerCollection.points, s.points)
+    simplePlayerCollection.eliminatedPieces = s.eliminatedPieces
+    simplePlayerCollection.zombiePoints = s.zombiePoints
+    if s.checkLimit > 0 {
+        simplePlayerCollection.setCheckLimit(s.checkLimit)
+    }
+    for color := range s.checks {
+        copy(simplePlayerCollection.checks[color], s.checks[color])
+    }
 
     return simplePlayerCollection, nil
 }
@@ -163,6 +403,28 @@ func (s *SimplePlayerCollection) ZobristHash() uint64 {
         }
     }
 
+    // points have no upper bound, so each score is mixed into the key of its color
+    if s.pointsRules != nil {
+        for color, points := range s.points {
+            hash ^= zobristMix(s.zobristPoints[color] + uint64(points))
+        }
+    }
+
+    if s.checkLimit > 0 {
+        for checked, received := range s.checks {
+            for checker, count := range received {
+                hash ^= s.zobristChecks[checked][checker][count]
+            }
+        }
+    }
+
     return hash
 }
 
+
+// the splitmix64 finalizer, nearby inputs give unrelated outputs
+func zobristMix(x uint64) uint64 {
+    x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+    x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+    return x ^ (x >> 31)
+}
@@ -1,10 +1,28 @@
 package chess
 
+// what happens to the pieces of eliminated players
+const (
+    ELIMINATED_WALLS = 0 // the pieces stay frozen on the board, capturing them scores nothing
+    ELIMINATED_REMOVED = 1 // the pieces are taken off the board
+    ELIMINATED_ZOMBIES = 2 // the pieces stay frozen on the board, capturing them scores the zombie points
+    ELIMINATED_RANDOM = 3 // the pieces keep their turns and make random moves
+)
+
 func createPlayerTransition(b *SimpleBoard, p *SimplePlayerCollection, inCheckmate bool, inStalemate bool, t *PlayerTransition) {
     oldCurrent := p.getCurrent()
     oldWinner := p.getWinner()
     oldGameOver := p.getGameOver()
+    oldHalfMoveClock := p.getHalfMoveClock()
+    oldDrawOffers := p.getDrawOffers()
     next, remaining := p.getNextAndRemaining()
+    turn := p.getNextTurn()
+
+    // a checkmated player only ends the game once no teammate is left
+    eliminated := -1
+    if inCheckmate {
+        eliminated = oldCurrent
+    }
+    remainingTeams := p.teamsRemaining(eliminated)
 
     var newCurrent int
     var newWinner int
@@ -18,22 +36,12 @@ func createPlayerTransition(b *SimpleBoard, p *SimplePlayerCollection, inCheckma
         newCurrent = oldCurrent
         newWinner = oldWinner
         newGameOver = true
-    } else if remaining == 1 {
+    } else if remainingTeams <= 1 {
         newCurrent = next
         newWinner = next
         newGameOver = true
-    } else if remaining == 2 {
-        if inCheckmate {
-            newCurrent = next
-            newWinner = next
-            newGameOver = true
-        } else {
-            newCurrent = next
-            newWinner = -1
-            newGameOver = false
-        }
     } else {
-        newCurrent = next
+        newCurrent = turn
         newWinner = -1
         newGameOver = false
     }
@@ -46,7 +54,141 @@ func createPlayerTransition(b *SimpleBoard, p *SimplePlayerCollection, inCheckma
     t.newWinner = newWinner
     t.oldGameOver = oldGameOver
     t.newGameOver = newGameOver
-    t.eliminated = inCheckmate
+    t.oldHalfMoveClock = oldHalfMoveClock
+    t.newHalfMoveClock = oldHalfMoveClock
+    t.oldDrawOffers = oldDrawOffers
+    t.newDrawOffers = oldDrawOffers
+    t.eliminated = false
+    t.eliminatedColor = oldCurrent
+    t.resigned = false
+    t.pointsColor = -1
+    t.points = 0
+    t.removedLocations = nil
+    t.removedPieces = nil
+    t.deposit = nil
+    t.checksColor = -1
+    t.checked = 0
+
+    // players whose pieces move randomly are already eliminated when they run out of moves
+    if inCheckmate && p.playersAlive[oldCurrent] {
+        t.eliminate(oldCurrent)
+    }
+}
+
+// remembers the pieces that are taken off the board so undo can put them back
+func (t *PlayerTransition) eliminate(color int) {
+    t.eliminated = true
+    t.eliminatedColor = color
+    t.removedLocations = nil
+    t.removedPieces = nil
+
+    if t.p.eliminatedPieces != ELIMINATED_REMOVED {
+        return
+    }
+
+    for y := 0; y < t.b.y; y++ {
+        for x := 0; x < t.b.x; x++ {
+            piece := t.b.pieces[y][x]
+            if piece == nil || piece.color != color {
+                continue
+            }
+
+            t.removedLocations = append(t.removedLocations, t.b.getIndex(x, y))
+            t.removedPieces = append(t.removedPieces, piece)
+        }
+    }
+}
+
+// the resigning player doesn't have to be the current player
+func createResignTransition(b *SimpleBoard, p *SimplePlayerCollection, color int, t *PlayerTransition) {
+    createPlayerTransition(b, p, true, false, t)
+
+    turn := p.getNextTurn()
+
+    if p.teamsRemaining(color) <= 1 {
+        winner := -1
+        for other := 0; other < p.getPlayers(); other++ {
+            if other != color && p.playersAlive[other] {
+                winner = other
+            }
+        }
+
+        t.newCurrent = t.oldCurrent
+        if winner >= 0 {
+            t.newCurrent = winner
+        }
+        t.newWinner = winner
+        t.newGameOver = true
+    } else {
+        t.newCurrent = t.oldCurrent
+        if color == t.oldCurrent {
+            t.newCurrent = turn
+        }
+        t.newWinner = -1
+        t.newGameOver = false
+    }
+
+    t.eliminate(color)
+    t.resigned = true
+}
+
+// the game is drawn once every remaining player offered or accepted a draw
+func createDrawTransition(b *SimpleBoard, p *SimplePlayerCollection, color int, t *PlayerTransition) {
+    createPlayerTransition(b, p, false, false, t)
+
+    t.newCurrent = t.oldCurrent
+    t.newWinner = t.oldWinner
+    t.newGameOver = t.oldGameOver
+    t.newDrawOffers = t.oldDrawOffers | (1 << color)
+
+    for other := 0; other < p.getPlayers(); other++ {
+        if p.playersAlive[other] && t.newDrawOffers & (1 << other) == 0 {
+            return
+        }
+    }
+
+    t.newWinner = -1
+    t.newGameOver = true
+}
+
+// with points a leading player ends the game once two players are left
+func createClaimWinTransition(b *SimpleBoard, p *SimplePlayerCollection, t *PlayerTransition) {
+    createPlayerTransition(b, p, false, false, t)
+
+    t.newCurrent = t.oldCurrent
+    t.newGameOver = true
+}
+
+// a piece captured on a linked bughouse board goes into the reserve without changing the turn
+func createDepositTransition(b *SimpleBoard, p *SimplePlayerCollection, deposit *Piece, t *PlayerTransition) {
+    createPlayerTransition(b, p, false, false, t)
+
+    t.newCurrent = t.oldCurrent
+    t.deposit = deposit
+}
+
+// a linked bughouse board ended, so this board ends with it
+func createEndTransition(b *SimpleBoard, p *SimplePlayerCollection, winner int, t *PlayerTransition) {
+    createPlayerTransition(b, p, false, false, t)
+
+    t.newCurrent = t.oldCurrent
+    t.newWinner = winner
+    t.newGameOver = true
+}
+
+// captures and pawn moves reset the clock, called before the move is executed
+func (t *PlayerTransition) countHalfMove(b *SimpleBoard, move *FastMove) {
+    piece := b.getPiece(move.fromLocation)
+    if move.captureValue > 0 || (piece != nil && piece.isPawn()) {
+        t.newHalfMoveClock = 0
+    } else {
+        t.newHalfMoveClock = t.oldHalfMoveClock + 1
+    }
+}
+
+// moving declines the draw offers of the other players
+func (t *PlayerTransition) keepDrawOffer(color int) {
+    t.newDrawOffers = t.oldDrawOffers & (1 << color)
 }
 
 type PlayerTransition struct {
@@ -58,32 +200,72 @@ type PlayerTransition struct {
     newWinner int
     oldGameOver bool
     newGameOver bool
+    oldHalfMoveClock int
+    newHalfMoveClock int
+    oldDrawOffers int
+    newDrawOffers int
     eliminated bool
+    eliminatedColor int
+    resigned bool
+    pointsColor int // the color scoring in this transition, -1 when nobody scores
+    points int
+    removedLocations []*Point // pieces of the eliminated player taken off the board
+    removedPieces []*Piece
+    deposit *Piece // piece put into the reserve from a linked board, nil otherwise
+    checksColor int // the color giving check in this transition, -1 when nobody counts checks
+    checked int // bit set of the colors receiving a check from the checks color
 }
 
 func (s *PlayerTransition) execute() {
+    s.p.addPoints(s.pointsColor, s.points)
+    s.p.addChecks(s.checksColor, s.checked, 1)
     s.p.setCurrent(s.newCurrent)
     s.p.setWinner(s.newWinner)
     s.p.setGameOver(s.newGameOver)
+    if s.newGameOver {
+        s.p.setWinner(s.p.pointsWinner(s.newWinner))
+    }
+    s.p.setHalfMoveClock(s.newHalfMoveClock)
+    s.p.setDrawOffers(s.newDrawOffers)
+
+    if s.deposit != nil {
+        s.b.reserves[s.deposit.color][s.deposit.index]++
+    }
 
     if !s.eliminated {
         return
     }
 
-    s.p.eliminate(s.oldCurrent)
-    s.b.disablePieces(s.oldCurrent, true)
+    s.p.eliminate(s.eliminatedColor)
+    s.b.disablePieces(s.eliminatedColor, s.p.eliminatedPieces != ELIMINATED_RANDOM)
+
+    for _, location := range s.removedLocations {
+        s.b.setPiece(location, nil)
+    }
 }
 
 func (s *PlayerTransition) undo() {
+    s.p.addPoints(s.pointsColor, -s.points)
+    s.p.addChecks(s.checksColor, s.checked, -1)
     s.p.setCurrent(s.oldCurrent)
     s.p.setWinner(s.oldWinner)
     s.p.setGameOver(s.oldGameOver)
+    s.p.setHalfMoveClock(s.oldHalfMoveClock)
+    s.p.setDrawOffers(s.oldDrawOffers)
+
+    if s.deposit != nil {
+        s.b.reserves[s.deposit.color][s.deposit.index]--
+    }
 
     if !s.eliminated {
         return
     }
 
-    s.p.restore(s.oldCurrent)
-    s.b.disablePieces(s.oldCurrent, false)
+    s.p.restore(s.eliminatedColor)
+    s.b.disablePieces(s.eliminatedColor, false)
+
+    for i, location := range s.removedLocations {
+        s.b.setPiece(location, s.removedPieces[i])
+    }
 }
 
@@ -56,3 +56,40 @@ func Test_IncrementalTransition_noCheckmate(t *testing.T) {
     assert.Equal(t, p.getCurrent(), white)
 }
 
+
+func Test_IncrementalTransition_teams(t *testing.T) {
+    white := 0
+    black := 1
+    gray := 2
+
+    b, err := newSimpleBoard(10, 10, 4)
+    assert.Nil(t, err)
+
+    p, err := newSimplePlayerCollection(4)
+    assert.Nil(t, err)
+    p.setTeams([]int{0, 1, 0, 1})
+
+    var transition PlayerTransition
+    createPlayerTransition(b, p, true, false, &transition)
+
+    transition.execute()
+    assert.Equal(t, p.getWinner(), -1)
+    assert.Equal(t, p.getGameOver(), false)
+    assert.Equal(t, p.getCurrent(), black)
+    assert.Equal(t, p.teamsRemaining(-1), 2)
+
+    p.eliminate(gray)
+    p.setCurrent(gray)
+    transition.undo()
+
+    createPlayerTransition(b, p, true, false, &transition)
+
+    transition.execute()
+    assert.Equal(t, p.getWinner(), black)
+    assert.Equal(t, p.getGameOver(), true)
+    assert.Equal(t, p.teamsRemaining(-1), 1)
+
+    transition.undo()
+    assert.Equal(t, p.getWinner(), -1)
+    assert.Equal(t, p.getCurrent(), white)
+}
@@ -0,0 +1,110 @@
+package chess
+
+import "fmt"
+
+/*
+Responsible for:
+- keeping track of the rules of points based free for all games
+*/
+type PointsRules struct {
+    Captures map[string]int // points for capturing a piece by its letter, pieces of eliminated players are only worth the zombie points
+    Checkmate int // points for the player who checkmates an opponent
+    Stalemate int // points for the player who stalemates an opponent, stalemated players are eliminated
+    DoubleCheck int // points for checking two opponents with one move
+    TripleCheck int // points for checking three opponents with one move
+    ClaimWin int // lead that ends the game once two players are left, 0 plays until the end
+    Hill int // points for the king reaching the hill in king of the hill games, which ends the game
+}
+
+// the free for all rules of chess.com
+func NewFreeForAllPointsRules() *PointsRules {
+    return &PointsRules{
+        Captures: map[string]int{"P": 1, "N": 3, "B": 5, "R": 5, "Q": 9},
+        Checkmate: 20,
+        Stalemate: 20,
+        DoubleCheck: 5,
+        TripleCheck: 20,
+        ClaimWin: 21,
+    }
+}
+
+func (r *PointsRules) Validate() error {
+    for name, points := range r.Captures {
+        if pieceIndexFromName(name) < 0 {
+            return fmt.Errorf("invalid points piece %s", name)
+        }
+
+        if points < 0 {
+            return fmt.Errorf("invalid points for piece %s", name)
+        }
+    }
+
+    if r.Checkmate < 0 || r.Stalemate < 0 || r.DoubleCheck < 0 || r.TripleCheck < 0 || r.ClaimWin < 0 || r.Hill < 0 {
+        return fmt.Errorf("invalid points")
+    }
+
+    return nil
+}
+
+// points for capturing each piece index, so captures don't need a map lookup while searching
+func (r *PointsRules) capturePoints() []int {
+    capturePoints := make([]int, totalPieces())
+    for index := range capturePoints {
+        capturePoints[index] = r.Captures[piece_types[index].name]
+    }
+
+    return capturePoints
+}
+
+// called after the move is executed and the moves are calculated, the mover scores for captures and checks
+func (t *PlayerTransition) countPoints(b *SimpleBoard, move *FastMove) {
+    p := t.p
+    if p.pointsRules == nil || !p.playersAlive[move.color] {
+        return
+    }
+
+    points := 0
+    for i := 1; i < move.oldPiece.count; i++ {
+        piece := move.oldPiece.at(i)
+        if piece == nil || b.allied(piece.color, move.color) {
+            continue
+        }
+
+        if p.playersAlive[piece.color] {
+            points += p.capturePoints[piece.index]
+        } else if p.eliminatedPieces == ELIMINATED_ZOMBIES || p.eliminatedPieces == ELIMINATED_RANDOM {
+            points += p.zombiePoints
+        }
+    }
+
+    checks := 0
+    for color := 0; color < p.getPlayers(); color++ {
+        if p.playersAlive[color] && !b.allied(color, move.color) && b.Check(color) {
+            checks++
+        }
+    }
+
+    if checks == 2 {
+        points += p.pointsRules.DoubleCheck
+    } else if checks >= 3 {
+        points += p.pointsRules.TripleCheck
+    }
+
+    t.pointsColor = move.color
+    t.points = points
+}
+
+// the player who made the last move scores for checkmating or stalemating the current player, eliminated random movers don't score
+func (t *PlayerTransition) countEliminationPoints(mover int, inCheckmate bool) {
+    p := t.p
+    if p.pointsRules == nil || mover < 0 || !p.playersAlive[mover] || p.allied(mover, t.eliminatedColor) {
+        return
+    }
+
+    t.pointsColor = mover
+    if inCheckmate {
+        t.points = p.pointsRules.Checkmate
+    } else {
+        t.points = p.pointsRules.Stalemate
+    }
+}
@@ -0,0 +1,194 @@
+package chess
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_PointsRules_Validate(t *testing.T) {
+    assert.Nil(t, NewFreeForAllPointsRules().Validate())
+
+    for _, rules := range []PointsRules{
+        {Captures: map[string]int{"X": 1}},
+        {Captures: map[string]int{"Q": -9}},
+        {Checkmate: -20},
+        {ClaimWin: -1},
+    } {
+        assert.NotNil(t, rules.Validate(), rules)
+    }
+}
+
+func Test_Points_CapturesAndChecks(t *testing.T) {
+    white := 0
+    black := 1
+    gray := 2
+    blue := 3
+
+    rules := NewFreeForAllPointsRules()
+    rules.ClaimWin = 10
+
+    game, err := NewGameFromVariant(&VariantDefinition{
+        Width: 6,
+        Height: 6,
+        Players: 4,
+        Points: rules,
+        Pieces: []VariantPiece{
+            {X: 5, Y: 5, Color: white, Type: "K", Orientation: "U", Moved: true},
+            {X: 2, Y: 4, Color: white, Type: "N"},
+            {X: 0, Y: 0, Color: black, Type: "K", Orientation: "R", Moved: true},
+            {X: 1, Y: 2, Color: black, Type: "Q"},
+            {X: 4, Y: 0, Color: gray, Type: "K", Orientation: "D", Moved: true},
+            {X: 3, Y: 3, Color: blue, Type: "K", Orientation: "L", Moved: true},
+        },
+    })
+    assert.Nil(t, err)
+
+    err = game.Execute(2, 4, 1, 2, "") // white knight captures the queen and checks black and blue
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []int{14, 0, 0, 0}, state.Points)
+    assert.Equal(t, []int{white, black, gray, blue}, state.Ranking)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Resign(gray)
+    assert.Nil(t, err)
+    err = game.Resign(blue)
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, true, state.GameOver) // white claims the win with a lead of 14
+    assert.Equal(t, white, state.WinningPlayer)
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []int{0, 0, 0, 0}, state.Points)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Redo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []int{14, 0, 0, 0}, state.Points)
+    assert.Equal(t, true, state.GameOver)
+}
+
+func Test_Points_Elimination(t *testing.T) {
+    white := 0
+    black := 1
+
+    b, err := newSimpleBoard(8, 8, 4)
+    assert.Nil(t, err)
+
+    p, err := newSimplePlayerCollection(4)
+    assert.Nil(t, err)
+    p.setPointsRules(NewFreeForAllPointsRules())
+    p.addPoints(black, 30)
+
+    var transition PlayerTransition
+    createPlayerTransition(b, p, true, false, &transition)
+    transition.countEliminationPoints(black, true)
+
+    transition.execute()
+    assert.Equal(t, 50, p.getPoints(black))
+    assert.Equal(t, false, p.playersAlive[white])
+
+    transition.undo()
+    assert.Equal(t, 30, p.getPoints(black))
+    assert.Equal(t, true, p.playersAlive[white])
+
+    // the game ends with the points leader as the winner, even if they were eliminated
+    p.eliminate(2)
+    p.eliminate(3)
+    p.addPoints(white, 40)
+    createPlayerTransition(b, p, true, false, &transition)
+    transition.countEliminationPoints(black, false)
+
+    transition.execute()
+    assert.Equal(t, true, p.getGameOver())
+    assert.Equal(t, black, p.getWinner())
+    assert.Equal(t, []int{black, white, 2, 3}, p.ranking())
+
+    p.addPoints(white, 10)
+    transition.undo()
+    transition.execute()
+    assert.Equal(t, -1, p.getWinner()) // tied for the lead
+}
+
+func Test_Points_EliminatedMoverElimination(t *testing.T) {
+    white := 0
+    red := 2
+
+    b, err := newSimpleBoard(8, 8, 4)
+    assert.Nil(t, err)
+
+    p, err := newSimplePlayerCollection(4)
+    assert.Nil(t, err)
+    p.setPointsRules(NewFreeForAllPointsRules())
+    p.setEliminatedPieces(ELIMINATED_RANDOM, 0)
+    p.eliminate(red)
+
+    // a random mover that checkmates a live player doesn't score
+    var transition PlayerTransition
+    createPlayerTransition(b, p, true, false, &transition)
+    transition.countEliminationPoints(red, true)
+
+    transition.execute()
+    assert.Equal(t, 0, p.getPoints(red))
+    assert.Equal(t, false, p.playersAlive[white])
+}
+
+func Test_Points_ZobristHash(t *testing.T) {
+    black := 1
+
+    p, err := newSimplePlayerCollection(2)
+    assert.Nil(t, err)
+    hash := p.ZobristHash()
+
+    p.addPoints(black, 20)
+    assert.Equal(t, hash, p.ZobristHash()) // without points rules the points don't matter
+
+    p.setPointsRules(NewFreeForAllPointsRules())
+    hash = p.ZobristHash()
+
+    p.addPoints(black, 1)
+    assert.NotEqual(t, hash, p.ZobristHash())
+
+    p.addPoints(black, -1)
+    assert.Equal(t, hash, p.ZobristHash())
+}
+
+func Test_Points_SearcherElimination(t *testing.T) {
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "Points": {"Checkmate": 20, "Captures": {"P": 5}},
+        "Pieces": [
+            {"X": 6, "Y": 2, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 6, "Color": 0, "Type": "R", "Moved": true},
+            {"X": 7, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 3, "Y": 6, "Color": 1, "Type": "P", "Orientation": "D", "Moved": true}
+        ]
+    }`)
+    b := game.getBoard()
+    p := game.getPlayerCollection()
+    hash := b.ZobristHash() ^ p.ZobristHash()
+
+    // the checkmate scores more than the pawn, so it wins on points
+    stop := make(chan bool)
+    searcher := newParallelSearcher(b, p, stop)
+    moveKey, err := searcher.searchWithMinimax(2)
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: 0, YFrom: 6, XTo: 0, YTo: 0}, moveKey)
+
+    assert.Equal(t, hash, b.ZobristHash() ^ p.ZobristHash())
+    assert.Equal(t, []int{0, 0}, p.points)
+}
@@ -0,0 +1,444 @@
+package chess
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+/*
+Responsible for:
+- converting moves to and from standard algebraic notation
+*/
+func moveSAN(b *SimpleBoard, p *SimplePlayerCollection, move *FastMove) (string, error) {
+    san, err := moveSANWithoutCheck(b, move)
+    if err != nil {
+        return "", err
+    }
+
+    return san + checkSuffix(b, p, move), nil
+}
+
+func moveLAN(b *SimpleBoard, p *SimplePlayerCollection, move *FastMove) string {
+    if move.drop != nil {
+        return dropSAN(b, move) + checkSuffix(b, p, move)
+    }
+
+    piece := b.getPiece(move.fromLocation)
+
+    if castle := castleSAN(b, move); castle != "" {
+        return castle + checkSuffix(b, p, move)
+    }
+
+    lan := ""
+    if !piece.isPawn() {
+        lan += piece.print()
+    }
+
+    lan += squareName(b, move.fromLocation)
+
+    if moveCaptures(move) {
+        lan += "x"
+    } else {
+        lan += "-"
+    }
+
+    lan += squareName(b, move.toLocation)
+
+    if move.promotionIndex >= 0 {
+        lan += "=" + piece_types[move.promotionIndex].name
+    }
+
+    return lan + checkSuffix(b, p, move)
+}
+
+func moveSANWithoutCheck(b *SimpleBoard, move *FastMove) (string, error) {
+    if move.drop != nil {
+        return dropSAN(b, move), nil
+    }
+
+    piece := b.getPiece(move.fromLocation)
+
+    if castle := castleSAN(b, move); castle != "" {
+        return castle, nil
+    }
+
+    san := ""
+    capture := moveCaptures(move)
+
+    if piece.isPawn() {
+        if capture && isPawnLR(piece.index) {
+            san += strconv.Itoa(b.y - move.fromLocation.y)
+        } else if capture {
+            san += fileName(move.fromLocation.x)
+        }
+    } else {
+        san += piece.print()
+
+        legalMoves, err := b.LegalMovesOfColor(move.color)
+        if err != nil {
+            return "", err
+        }
+        san += disambiguation(b, move, legalMoves)
+    }
+
+    if capture {
+        san += "x"
+    }
+
+    san += squareName(b, move.toLocation)
+
+    if move.promotionIndex >= 0 {
+        san += "=" + piece_types[move.promotionIndex].name
+    }
+
+    return san, nil
+}
+
+// drops are written as N@f3, pawns included
+func dropSAN(b *SimpleBoard, move *FastMove) string {
+    return dropName(move) + "@" + squareName(b, move.toLocation)
+}
+
+// castling moves the king onto its own rook
+func castleSAN(b *SimpleBoard, move *FastMove) string {
+    piece := b.getPiece(move.fromLocation)
+    toPiece := b.getPiece(move.toLocation)
+
+    if piece == nil || !piece.isKing() || toPiece == nil || toPiece.color != piece.color {
+        return ""
+    }
+
+    kingSide := move.toLocation.x > move.fromLocation.x
+    if !isKingUD(piece.index) {
+        kingSide = move.toLocation.y > move.fromLocation.y
+    }
+
+    if kingSide {
+        return "O-O"
+    }
+    return "O-O-O"
+}
+
+func disambiguation(b *SimpleBoard, move *FastMove, legalMoves []FastMove) string {
+    piece := b.getPiece(move.fromLocation)
+
+    ambiguous := false
+    sameFile := false
+    sameRank := false
+    for _, other := range legalMoves {
+        if other.allyDefense || other.drop != nil || other.toLocation != move.toLocation || other.fromLocation == move.fromLocation {
+            continue
+        }
+
+        otherPiece := b.getPiece(other.fromLocation)
+        if otherPiece.print() != piece.print() {
+            continue
+        }
+
+        ambiguous = true
+        if other.fromLocation.x == move.fromLocation.x {
+            sameFile = true
+        }
+        if other.fromLocation.y == move.fromLocation.y {
+            sameRank = true
+        }
+    }
+
+    if !ambiguous {
+        return ""
+    } else if !sameFile {
+        return fileName(move.fromLocation.x)
+    } else if !sameRank {
+        return strconv.Itoa(b.y - move.fromLocation.y)
+    }
+
+    return squareName(b, move.fromLocation)
+}
+
+func checkSuffix(b *SimpleBoard, p *SimplePlayerCollection, move *FastMove) string {
+    suffix := ""
+    next, _ := p.getNextAndRemaining()
+
+    move.execute()
+    b.CalculateMoves()
+
+    for color := 0; color < b.players; color++ {
+        if color == move.color || !p.playersAlive[color] || !b.Check(color) {
+            continue
+        }
+
+        suffix = "+"
+        if color != next {
+            continue
+        }
+
+        if checkmate, _, err := b.CheckmateAndStalemate(color); err == nil && checkmate {
+            suffix = "#"
+            break
+        }
+    }
+
+    move.undo()
+    b.CalculateMoves()
+
+    return suffix
+}
+
+func moveCaptures(move *FastMove) bool {
+    for i := 1; i < move.oldPiece.count; i++ {
+        if move.oldPiece.at(i) != nil {
+            return true
+        }
+    }
+
+    return false
+}
+
+func isKingUD(index int) bool {
+    return index == KING_U || index == KING_D || index == KING_U_M || index == KING_D_M
+}
+
+func isPawnLR(index int) bool {
+    return index == PAWN_L || index == PAWN_R || index == PAWN_L_M || index == PAWN_R_M
+}
+
+func sanToMove(b *SimpleBoard, color int, san string) (*FastMove, error) {
+    san = strings.TrimRight(san, "+#!?")
+
+    legalMoves, err := b.LegalMovesOfColor(color)
+    if err != nil {
+        return nil, err
+    }
+
+    if castle := strings.ReplaceAll(san, "0", "O"); castle == "O-O" || castle == "O-O-O" {
+        return castleToMove(b, legalMoves, castle)
+    }
+
+    if strings.Contains(san, "@") {
+        return dropToMove(b, legalMoves, san)
+    }
+
+    san, promotion := splitPromotion(san)
+
+    // the destination is the longest trailing square on the board, since files can have several letters
+    rank := len(san)
+    for rank > 0 && san[rank-1] >= '0' && san[rank-1] <= '9' {
+        rank--
+    }
+    start := rank
+    for start > 0 && san[start-1] >= 'a' && san[start-1] <= 'z' && san[start-1] != 'x' {
+        start--
+    }
+
+    i := start
+    var toLocation *Point
+    for ; i < rank && toLocation == nil; i++ {
+        toLocation, _ = parseSquare(b, san[i:])
+    }
+    i--
+
+    if toLocation == nil {
+        return nil, fmt.Errorf("invalid san %s", san)
+    }
+
+    prefix := strings.TrimSuffix(san[:i], "x")
+
+    name := "P"
+    if len(prefix) > 0 && prefix[0] >= 'A' && prefix[0] <= 'Z' {
+        name = prefix[:1]
+        prefix = prefix[1:]
+    }
+
+    fromFile := ""
+    fromRank := ""
+    for j := 0; j < len(prefix); j++ {
+        if prefix[j] >= 'a' && prefix[j] <= 'z' {
+            fromFile += prefix[j:j+1]
+        } else if prefix[j] >= '0' && prefix[j] <= '9' {
+            fromRank += prefix[j:j+1]
+        } else {
+            return nil, fmt.Errorf("invalid san %s", san)
+        }
+    }
+
+    var found *FastMove
+    for j := range legalMoves {
+        move := &legalMoves[j]
+        if move.allyDefense || move.drop != nil || move.toLocation != toLocation || castleSAN(b, move) != "" {
+            continue
+        }
+
+        if b.getPiece(move.fromLocation).print() != name {
+            continue
+        }
+
+        if fromFile != "" && fileName(move.fromLocation.x) != fromFile {
+            continue
+        }
+        if fromRank != "" && strconv.Itoa(b.y - move.fromLocation.y) != fromRank {
+            continue
+        }
+
+        if promotionName(move) != promotion {
+            continue
+        }
+
+        if found != nil {
+            return nil, fmt.Errorf("ambiguous move")
+        }
+        found = move
+    }
+
+    if found == nil {
+        return nil, fmt.Errorf("illegal move")
+    }
+
+    return found, nil
+}
+
+func lanToMove(b *SimpleBoard, color int, lan string) (*FastMove, error) {
+    lan = strings.TrimRight(lan, "+#!?")
+
+    legalMoves, err := b.LegalMovesOfColor(color)
+    if err != nil {
+        return nil, err
+    }
+
+    if castle := strings.ReplaceAll(lan, "0", "O"); castle == "O-O" || castle == "O-O-O" {
+        return castleToMove(b, legalMoves, castle)
+    }
+
+    if strings.Contains(lan, "@") {
+        return dropToMove(b, legalMoves, lan)
+    }
+
+    lan, promotion := splitPromotion(lan)
+
+    name := "P"
+    if len(lan) > 0 && lan[0] >= 'A' && lan[0] <= 'Z' {
+        name = lan[:1]
+        lan = lan[1:]
+    }
+
+    separator := strings.IndexAny(lan, "-x")
+    if separator < 0 {
+        return nil, fmt.Errorf("invalid lan %s", lan)
+    }
+
+    fromLocation, err := parseSquare(b, lan[:separator])
+    if err != nil {
+        return nil, err
+    }
+
+    toLocation, err := parseSquare(b, lan[separator+1:])
+    if err != nil {
+        return nil, err
+    }
+
+    for i := range legalMoves {
+        move := &legalMoves[i]
+        if move.allyDefense || move.drop != nil || move.fromLocation != fromLocation || move.toLocation != toLocation || castleSAN(b, move) != "" {
+            continue
+        }
+
+        if b.getPiece(move.fromLocation).print() == name && promotionName(move) == promotion {
+            return move, nil
+        }
+    }
+
+    return nil, fmt.Errorf("illegal move")
+}
+
+// drops are the same in san and lan, the letter can be left out for pawns
+func dropToMove(b *SimpleBoard, legalMoves []FastMove, san string) (*FastMove, error) {
+    at := strings.IndexByte(san, '@')
+
+    name := san[:at]
+    if name == "" {
+        name = "P"
+    }
+
+    toLocation, err := parseSquare(b, san[at+1:])
+    if err != nil {
+        return nil, err
+    }
+
+    for i := range legalMoves {
+        if legalMoves[i].toLocation == toLocation && dropName(&legalMoves[i]) == name {
+            return &legalMoves[i], nil
+        }
+    }
+
+    return nil, fmt.Errorf("illegal drop")
+}
+
+func castleToMove(b *SimpleBoard, legalMoves []FastMove, castle string) (*FastMove, error) {
+    for i := range legalMoves {
+        if !legalMoves[i].allyDefense && castleSAN(b, &legalMoves[i]) == castle {
+            return &legalMoves[i], nil
+        }
+    }
+
+    return nil, fmt.Errorf("illegal move")
+}
+
+// promotions are written as e8=Q or e8Q
+func splitPromotion(san string) (string, string) {
+    if i := strings.IndexByte(san, '='); i >= 0 {
+        return san[:i], san[i+1:]
+    } else if len(san) > 0 && san[len(san)-1] >= 'A' && san[len(san)-1] <= 'Z' {
+        return san[:len(san)-1], san[len(san)-1:]
+    }
+
+    return san, ""
+}
+
+func promotionName(move *FastMove) string {
+    if move.promotionIndex < 0 {
+        return ""
+    }
+
+    return piece_types[move.promotionIndex].name
+}
+
+func (s *SimpleGame) ExecuteSAN(san string) error {
+    gameOver := s.p.getGameOver()
+    if gameOver {
+        return fmt.Errorf("game is over")
+    }
+
+    move, err := sanToMove(s.b, s.p.getCurrent(), san)
+    if err != nil {
+        return err
+    }
+
+    return s.executeMove(*move)
+}
+
+func (s *SimpleGame) MoveToSAN(moveKey MoveKey) (string, error) {
+    move, err := s.legalMoveKey(moveKey)
+    if err != nil {
+        return "", err
+    }
+
+    return moveSAN(s.b, s.p, move)
+}
+
+func (s *SimpleGame) MoveToLAN(moveKey MoveKey) (string, error) {
+    move, err := s.legalMoveKey(moveKey)
+    if err != nil {
+        return "", err
+    }
+
+    return moveLAN(s.b, s.p, move), nil
+}
+
+func (s *SimpleGame) SANToMove(san string) (MoveKey, error) {
+    move, err := sanToMove(s.b, s.p.getCurrent(), san)
+    if err != nil {
+        return MoveKey{XFrom: -1, YFrom: -1, XTo: -1, YTo: -1}, err
+    }
+
+    return createMoveKey(move), nil
+}
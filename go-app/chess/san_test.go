@@ -0,0 +1,162 @@
+package chess
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_SAN_SanToMove(t *testing.T) {
+    white := 0
+    black := 1
+
+    b, _, _, err := createSimpleBoardFromFEN("r3k3/1P6/8/8/8/8/8/R3K2R w KQq - 0 1")
+    assert.Nil(t, err)
+
+    move, err := sanToMove(b, white, "0-0")
+    assert.Nil(t, err)
+    assert.Equal(t, b.getIndex(7, 7), move.toLocation)
+
+    move, err = sanToMove(b, white, "bxa8=N+")
+    assert.Nil(t, err)
+    assert.Equal(t, KNIGHT, move.promotionIndex)
+
+    move, err = sanToMove(b, white, "b8Q")
+    assert.Nil(t, err)
+    assert.Equal(t, QUEEN, move.promotionIndex)
+
+    move, err = sanToMove(b, white, "Rhg1")
+    assert.Nil(t, err)
+    assert.Equal(t, b.getIndex(7, 7), move.fromLocation)
+
+    _, err = sanToMove(b, black, "O-O-O") // castle through attacked square
+    assert.NotNil(t, err)
+
+    _, err = sanToMove(b, white, "Rb2")
+    assert.NotNil(t, err)
+
+    _, err = sanToMove(b, white, "b8")
+    assert.NotNil(t, err)
+
+    _, err = sanToMove(b, black, "O-O")
+    assert.NotNil(t, err)
+}
+
+func Test_SAN_MoveSAN(t *testing.T) {
+    white := 0
+
+    b, p, _, err := createSimpleBoardFromFEN("r3k3/1P6/8/8/8/8/8/R3K2R w KQq - 0 1")
+    assert.Nil(t, err)
+
+    moves, err := b.LegalMovesOfColor(white)
+    assert.Nil(t, err)
+
+    sans := map[string]bool{}
+    for i := range moves {
+        san, err := moveSAN(b, p, &moves[i])
+        assert.Nil(t, err)
+        sans[san] = true
+
+        move, err := sanToMove(b, white, san)
+        assert.Nil(t, err)
+        assert.Equal(t, moves[i].fromLocation, move.fromLocation)
+        assert.Equal(t, moves[i].toLocation, move.toLocation)
+        assert.Equal(t, moves[i].promotionIndex, move.promotionIndex)
+    }
+
+    for _, san := range []string{"O-O", "O-O-O", "bxa8=Q+", "b8=R+", "Rd1", "Rf1", "Rxa8+", "Kf2"} {
+        assert.True(t, sans[san], san)
+    }
+}
+
+func Test_SAN_ExecuteSAN(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    err = game.ExecuteSAN("e4")
+    assert.Nil(t, err)
+    err = game.ExecuteSAN("e4")
+    assert.NotNil(t, err)
+    err = game.ExecuteSAN("Nf6")
+    assert.Nil(t, err)
+
+    san, err := game.MoveToSAN(MoveKey{XFrom: 4, YFrom: 4, XTo: 4, YTo: 3})
+    assert.Nil(t, err)
+    assert.Equal(t, "e5", san)
+
+    lan, err := game.MoveToLAN(MoveKey{XFrom: 6, YFrom: 7, XTo: 5, YTo: 5})
+    assert.Nil(t, err)
+    assert.Equal(t, "Ng1-f3", lan)
+
+    moveKey, err := game.SANToMove("Nc3")
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: 1, YFrom: 7, XTo: 2, YTo: 5}, moveKey)
+
+    _, err = game.MoveToSAN(MoveKey{XFrom: 4, YFrom: 4, XTo: 4, YTo: 2})
+    assert.NotNil(t, err)
+}
+
+func Test_SAN_Promotion(t *testing.T) {
+    game, err := NewSimpleGameFromFEN("4k3/P7/8/8/8/8/8/4K3 w - - 0 1")
+    assert.Nil(t, err)
+
+    moves, err := game.Moves(0)
+    assert.Nil(t, err)
+
+    promotions := []string{}
+    for _, move := range moves {
+        if move.XFrom == 0 {
+            promotions = append(promotions, move.Promotion)
+        }
+    }
+    assert.ElementsMatch(t, []string{"N", "B", "R", "Q"}, promotions)
+
+    lan, err := game.MoveToLAN(MoveKey{XFrom: 0, YFrom: 1, XTo: 0, YTo: 0, Promotion: "R"})
+    assert.Nil(t, err)
+    assert.Equal(t, "a7-a8=R+", lan)
+
+    err = game.ExecuteSAN("a8=N")
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "N3k3/8/8/8/8/8/8/4K3 b - - 0 1", fen)
+}
+
+func Test_SAN_WideBoard(t *testing.T) {
+    game, err := NewSimpleGameFromGFEN("28x2 0 1,1 13,1N,13,1KDm/0KUm,12,0N,14 -,- -,- 0 1")
+    assert.Nil(t, err)
+
+    err = game.ExecuteSAN("Kb1")
+    assert.Nil(t, err)
+
+    lan, err := game.MoveToLAN(MoveKey{XFrom: 27, YFrom: 0, XTo: 26, YTo: 0})
+    assert.Nil(t, err)
+    assert.Equal(t, "Kab2-aa2", lan)
+
+    err = game.ExecuteSAN("Kaa1")
+    assert.Nil(t, err)
+
+    moveKey, err := game.SANToMove("Kc2")
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: 1, YFrom: 1, XTo: 2, YTo: 0}, moveKey)
+}
+
+func Test_SAN_FourPlayer(t *testing.T) {
+    game, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    err = game.ExecuteSAN("d4")
+    assert.Nil(t, err)
+
+    san, err := game.MoveToSAN(MoveKey{XFrom: 1, YFrom: 9, XTo: 3, YTo: 9})
+    assert.Nil(t, err)
+    assert.Equal(t, "d5", san)
+
+    err = game.ExecuteSAN("d5")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 2, state.CurrentPlayer)
+}
@@ -3,6 +3,7 @@ package chess
 import (
     "math"
     "fmt"
+    "sync/atomic"
 )
 
 /*
@@ -26,6 +27,9 @@ func newSimpleSearcher(b *SimpleBoard, p *SimplePlayerCollection, stop chan bool
         b: b,
         p: p,
         e: newSimpleEvaluator(b, p),
+        rootMover: -1,
+
+        nodes: &atomic.Int64{},
 
         stop: stop,
         stopReached: false,
@@ -43,10 +47,14 @@ type SimpleSearcher struct {
     transitionLevels []PlayerTransition
     moveLevels []Array1000[FastMove]
     captureMoveLevels []Array1000[FastMove]
+    dropLevels [][]FastMove
     transpositionMapLevels []map[uint64][]int
 
     maxDepth int
     moveKey MoveKey
+    rootMover int // the color who moved into the searched position, -1 when it isn't known
+
+    nodes *atomic.Int64 // positions visited, can be shared between searchers
 
     stop chan bool
     stopReached bool
@@ -55,25 +63,27 @@ type SimpleSearcher struct {
 func (s *SimpleSearcher) searchWithMinimax(maxDepth int) (MoveKey, error) {
     s.players = s.p.getPlayers()
     s.maxDepth = maxDepth
-    s.moveKey = MoveKey{-1, -1, -1, -1, ""}
+    s.moveKey = MoveKey{XFrom: -1, YFrom: -1, XTo: -1, YTo: -1}
 
     s.scoreLevels = make([][]int, maxDepth+1)
     s.transitionLevels = make([]PlayerTransition, maxDepth+1)
     s.moveLevels = make([]Array1000[FastMove], maxDepth+1)
     s.captureMoveLevels = make([]Array1000[FastMove], maxDepth+1)
+    s.dropLevels = make([][]FastMove, maxDepth+1)
     s.transpositionMapLevels = make([]map[uint64][]int, maxDepth+1)
     for i := 0; i < maxDepth+1; i++ {
         s.scoreLevels[i] = make([]int, s.players)
         s.transitionLevels[i] = PlayerTransition{}
         s.moveLevels[i] = Array1000[FastMove]{}
         s.captureMoveLevels[i] = Array1000[FastMove]{}
+        s.dropLevels[i] = []FastMove{}
         s.transpositionMapLevels[i] = map[uint64][]int{}
     }
 
     s.b.CalculateMoves()
     s.minimax(0)
 
-    if s.moveKey.XTo == -1 || s.moveKey.YTo == -1 || s.moveKey.XFrom == -1 || s.moveKey.YFrom == -1 {
+    if s.moveKey.XTo == -1 || s.moveKey.YTo == -1 {
         return s.moveKey, fmt.Errorf("No move found")
     }
     return s.moveKey, nil
@@ -89,6 +99,22 @@ func (s *SimpleSearcher) minimax(depth int) {
         return
     }
 
+    s.nodes.Add(1)
+
+    // players checked too often are out before anyone moves, the transition levels belong to the moves
+    if checked := s.p.checkedOut(); checked >= 0 && !s.p.getGameOver() {
+        transition := PlayerTransition{}
+        createChecksTransition(s.b, s.p, checked, &transition)
+
+        transition.execute()
+        s.b.CalculateMoves()
+        s.minimax(depth)
+        transition.undo()
+        s.b.CalculateMoves()
+
+        return
+    }
+
     hash := s.b.ZobristHash() ^ s.p.ZobristHash()
 
     if _, ok := s.transpositionMapLevels[depth][hash]; ok {
@@ -101,7 +127,7 @@ func (s *SimpleSearcher) minimax(depth int) {
         return
     }
 
-    if depth >= s.maxDepth || s.p.getGameOver() {
+    if depth >= s.maxDepth || s.p.getGameOver() || s.b.InsufficientMaterial() {
         s.e.eval(s.scoreLevels[depth])
 
         newScore := make([]int, s.players)
@@ -121,20 +147,38 @@ func (s *SimpleSearcher) minimax(depth int) {
     }
 
     transition := &s.transitionLevels[depth]
-    found1 := s.recurse(depth, currentPlayer, &s.captureMoveLevels[depth], transition)
-    found2 := s.recurse(depth, currentPlayer, &s.moveLevels[depth], transition)
+    captureMoves := &s.captureMoveLevels[depth]
+    moves := &s.moveLevels[depth]
+    found1 := s.recurse(depth, currentPlayer, captureMoves.array[:captureMoves.count], transition)
+    found2 := s.recurse(depth, currentPlayer, moves.array[:moves.count], transition)
+    found3 := s.recurse(depth, currentPlayer, s.dropLevels[depth], transition)
 
-    if !found1 && !found2 {
+    if !found1 && !found2 && !found3 {
         s.b.CalculateMoves()
-        if s.b.Check(currentPlayer) {
-            createPlayerTransition(s.b, s.p, true, false, transition)
+
+        // the player who moved into this position scores the checkmate or stalemate like in the game
+        mover := s.rootMover
+        if depth > 0 {
+            mover = s.transitionLevels[depth-1].oldCurrent
+        }
+
+        // the search below reuses the transition of this level, so the elimination keeps its own
+        noMovesTransition := PlayerTransition{}
+        checkmate := s.b.Check(currentPlayer)
+
+        // eliminated players whose pieces move randomly pass without moves
+        if s.p.movesRandomly(currentPlayer) {
+            createPlayerTransition(s.b, s.p, true, false, &noMovesTransition)
+        } else if checkmate || s.p.pointsRules != nil {
+            createPlayerTransition(s.b, s.p, true, false, &noMovesTransition)
+            noMovesTransition.countEliminationPoints(mover, checkmate)
         } else {
-            createPlayerTransition(s.b, s.p, false, true, transition)
+            createPlayerTransition(s.b, s.p, false, true, &noMovesTransition)
         }
 
-        transition.execute()
+        noMovesTransition.execute()
         s.minimax(depth)
-        transition.undo()
+        noMovesTransition.undo()
     }
 
     newScore := make([]int, s.players)
@@ -160,13 +204,15 @@ func (s *SimpleSearcher) copyMoves(depth int, color int) {
     for i := 0; i < captureMoves.count; i++ {
         s.captureMoveLevels[depth].array[i] = captureMoves.array[i]
     }
+
+    s.dropLevels[depth] = append(s.dropLevels[depth][:0], s.b.drops[color]...)
 }
 
-func (s *SimpleSearcher) recurse(depth int, color int, moves *Array1000[FastMove], transition *PlayerTransition) bool {
+func (s *SimpleSearcher) recurse(depth int, color int, moves []FastMove, transition *PlayerTransition) bool {
     found := false
 
-    for i := 0; i < moves.count; i++ {
-        move := &moves.array[i]
+    for i := range moves {
+        move := &moves[i]
 
         move.execute()
 
@@ -177,6 +223,9 @@ func (s *SimpleSearcher) recurse(depth int, color int, moves *Array1000[FastMove
         }
 
         createPlayerTransition(s.b, s.p, false, false, transition)
+        transition.countPoints(s.b, move)
+        transition.countChecks(s.b, move)
+        transition.reachHill(s.b, color)
 
         transition.execute()
         s.minimax(depth+1)
@@ -191,11 +240,7 @@ func (s *SimpleSearcher) recurse(depth int, color int, moves *Array1000[FastMove
             }
 
             if depth <= 0 {
-                s.moveKey.XTo = move.toLocation.x
-                s.moveKey.YTo = move.toLocation.y
-                s.moveKey.XFrom = move.fromLocation.x
-                s.moveKey.YFrom = move.fromLocation.y
-                s.moveKey.Promotion = ""
+                s.moveKey = createMoveKey(move)
             }
         }
     }
@@ -218,6 +263,8 @@ func newParallelSearcher(b *SimpleBoard, p *SimplePlayerCollection, stop chan bo
         b: b,
         p: p,
 
+        nodes: &atomic.Int64{},
+
         stop: stop,
         stopReached: false,
     }
@@ -233,24 +280,28 @@ type ParallelSearcher struct {
     maxDepth int
     moveKey MoveKey
 
+    nodes *atomic.Int64 // positions visited by all of the searchers
+
     stop chan bool
     stopReached bool
 }
 
 func (s *ParallelSearcher) searchWithMinimax(maxDepth int) (MoveKey, error) {
     s.maxDepth = maxDepth
-    s.moveKey = MoveKey{-1, -1, -1, -1, ""}
+    s.moveKey = MoveKey{XFrom: -1, YFrom: -1, XTo: -1, YTo: -1}
 
     s.b.CalculateMoves()
     currentPlayer := s.p.getCurrent()
     moveCount := 0
     moveCount += s.b.captureMoves[currentPlayer].count
     moveCount += s.b.moves[currentPlayer].count
+    moveCount += len(s.b.drops[currentPlayer])
 
     s.result = make(chan *MoveKeyWithScore, moveCount)
     s.stops = make([]chan bool, moveCount)
+    // buffered so stopping doesn't block on searchers that already finished
     for i := 0; i < moveCount; i++ {
-        s.stops[i] = make(chan bool)
+        s.stops[i] = make(chan bool, 1)
     }
 
     for i := 0; i < moveCount; i++ {
@@ -264,7 +315,7 @@ func (s *ParallelSearcher) searchWithMinimax(maxDepth int) (MoveKey, error) {
             return s.moveKey, err
         }
 
-        go minimaxWrapper(boardCopy, playerCollectionCopy, s.stops[i], s.result, s.maxDepth-1, i)
+        go minimaxWrapper(boardCopy, playerCollectionCopy, s.stops[i], s.result, s.maxDepth-1, i, s.nodes)
     }
 
     counter := 0
@@ -287,6 +338,7 @@ func (s *ParallelSearcher) searchWithMinimax(maxDepth int) (MoveKey, error) {
                     s.moveKey.XFrom = moveKeyWithScorePtr.XFrom
                     s.moveKey.YFrom = moveKeyWithScorePtr.YFrom
                     s.moveKey.Promotion = moveKeyWithScorePtr.Promotion
+                    s.moveKey.Drop = moveKeyWithScorePtr.Drop
                 }
             } else {
                 fmt.Println("No move found")
@@ -297,13 +349,13 @@ func (s *ParallelSearcher) searchWithMinimax(maxDepth int) (MoveKey, error) {
         }
     }
 
-    if s.moveKey.XTo == -1 || s.moveKey.YTo == -1 || s.moveKey.XFrom == -1 || s.moveKey.YFrom == -1 {
+    if s.moveKey.XTo == -1 || s.moveKey.YTo == -1 {
         return s.moveKey, fmt.Errorf("No move found")
     }
     return s.moveKey, nil
 }
 
-func minimaxWrapper(b *SimpleBoard, p *SimplePlayerCollection, stop chan bool, result chan *MoveKeyWithScore, depth int, i int) {
+func minimaxWrapper(b *SimpleBoard, p *SimplePlayerCollection, stop chan bool, result chan *MoveKeyWithScore, depth int, i int, nodes *atomic.Int64) {
     b.CalculateMoves()
     currentPlayer := p.getCurrent()
 
@@ -312,7 +364,9 @@ func minimaxWrapper(b *SimpleBoard, p *SimplePlayerCollection, stop chan bool, r
     captureMoves := &b.captureMoves[currentPlayer]
     moves := &b.moves[currentPlayer]
 
-    if i >= captureMoves.count {
+    if i >= captureMoves.count + moves.count {
+        move = b.drops[currentPlayer][i-captureMoves.count-moves.count]
+    } else if i >= captureMoves.count {
         move = moves.array[i-captureMoves.count]
     } else {
         move = captureMoves.array[i]
@@ -325,17 +379,24 @@ func minimaxWrapper(b *SimpleBoard, p *SimplePlayerCollection, stop chan bool, r
         return
     }
     createPlayerTransition(b, p, false, false, &transition)
+    transition.countPoints(b, &move)
+    transition.countChecks(b, &move)
+    transition.reachHill(b, currentPlayer)
     transition.execute()
 
     searcher := newSimpleSearcher(b, p, stop)
+    searcher.nodes = nodes
+    searcher.rootMover = currentPlayer
 
     searcher.searchWithMinimax(depth)
+    moveKey := createMoveKey(&move)
     result <- &MoveKeyWithScore{
-        XTo: move.toLocation.x,
-        YTo: move.toLocation.y,
-        XFrom: move.fromLocation.x,
-        YFrom: move.fromLocation.y,
-        Promotion: "",
+        XTo: moveKey.XTo,
+        YTo: moveKey.YTo,
+        XFrom: moveKey.XFrom,
+        YFrom: moveKey.YFrom,
+        Promotion: moveKey.Promotion,
+        Drop: moveKey.Drop,
         Score: searcher.scoreLevels[0][currentPlayer],
     }
 }
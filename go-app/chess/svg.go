@@ -0,0 +1,122 @@
+package chess
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+/*
+Responsible for:
+- drawing the board state as an svg image with coordinates, arrows, and highlighted squares
+*/
+const SVG_DEFAULT_SQUARE_SIZE = 40
+
+var svg_player_colors = []string{"#ffffff", "#202020", "#d03030", "#30a030"}
+var svg_player_text_colors = []string{"#202020", "#ffffff", "#ffffff", "#ffffff"}
+
+type SVGOptions struct {
+    SquareSize int // defaults to SVG_DEFAULT_SQUARE_SIZE
+    Arrows []MoveKey // drawn from the from square to the to square
+    Highlights []SVGSquare
+}
+
+type SVGSquare struct {
+    X int
+    Y int
+}
+
+func RenderSVG(data *BoardData, options *SVGOptions) string {
+    if options == nil {
+        options = &SVGOptions{}
+    }
+
+    size := options.SquareSize
+    if size <= 0 {
+        size = SVG_DEFAULT_SQUARE_SIZE
+    }
+
+    // the margin on the left and bottom holds the coordinates
+    margin := size / 2
+    width := margin + data.XSize * size
+    height := data.YSize * size + margin
+
+    disabled := map[SVGSquare]bool{}
+    for _, square := range data.Disabled {
+        disabled[SVGSquare{square.X, square.Y}] = true
+    }
+
+    var builder strings.Builder
+
+    builder.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height))
+    builder.WriteString("\n")
+    builder.WriteString(`<defs><marker id="arrowhead" markerWidth="4" markerHeight="4" refX="2" refY="2" orient="auto"><path d="M0,0 L4,2 L0,4 z" fill="#f0a000"/></marker></defs>`)
+    builder.WriteString("\n")
+
+    for y := 0; y < data.YSize; y++ {
+        for x := 0; x < data.XSize; x++ {
+            fill := "#304030"
+            if disabled[SVGSquare{x, y}] {
+                fill = "dimgray"
+            } else if (x + y) % 2 == 0 {
+                fill = "#306010"
+            }
+
+            builder.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, margin + x * size, y * size, size, size, fill))
+            builder.WriteString("\n")
+        }
+    }
+
+    for _, square := range options.Highlights {
+        builder.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="#f0e000" fill-opacity="0.5"/>`, margin + square.X * size, square.Y * size, size, size))
+        builder.WriteString("\n")
+    }
+
+    for y := 0; y < data.YSize; y++ {
+        builder.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-family="sans-serif" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="#808080">%s</text>`, margin / 2, y * size + size / 2, size / 4, strconv.Itoa(data.YSize - y)))
+        builder.WriteString("\n")
+    }
+
+    for x := 0; x < data.XSize; x++ {
+        builder.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-family="sans-serif" font-size="%d" text-anchor="middle" dominant-baseline="central" fill="#808080">%s</text>`, margin + x * size + size / 2, data.YSize * size + margin / 2, size / 4, fileName(x)))
+        builder.WriteString("\n")
+    }
+
+    for _, piece := range data.Pieces {
+        fill := "#808080"
+        textFill := "#ffffff"
+        if piece.C >= 0 && piece.C < len(svg_player_colors) {
+            fill = svg_player_colors[piece.C]
+            textFill = svg_player_text_colors[piece.C]
+        }
+
+        // disabled pieces are faded like in the frontend
+        opacity := "1"
+        if piece.D {
+            opacity = "0.6"
+        }
+
+        centerX := margin + piece.X * size + size / 2
+        centerY := piece.Y * size + size / 2
+
+        builder.WriteString(fmt.Sprintf(`<g opacity="%s">`, opacity))
+        builder.WriteString(fmt.Sprintf(`<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="#000000"/>`, centerX, centerY, size * 2 / 5, fill))
+        builder.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-family="sans-serif" font-size="%d" font-weight="bold" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`, centerX, centerY, size / 2, textFill, piece.T))
+        builder.WriteString("</g>\n")
+    }
+
+    for _, arrow := range options.Arrows {
+        builder.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#f0a000" stroke-width="%d" stroke-opacity="0.8" marker-end="url(#arrowhead)"/>`,
+            margin + arrow.XFrom * size + size / 2,
+            arrow.YFrom * size + size / 2,
+            margin + arrow.XTo * size + size / 2,
+            arrow.YTo * size + size / 2,
+            max(size / 8, 1),
+        ))
+        builder.WriteString("\n")
+    }
+
+    builder.WriteString("</svg>\n")
+
+    return builder.String()
+}
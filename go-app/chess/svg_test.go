@@ -0,0 +1,55 @@
+package chess
+
+import (
+    "encoding/xml"
+    "strings"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_SVG_Default(t *testing.T) {
+    game, err := NewSimpleGame()
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+
+    svg := RenderSVG(state, nil)
+
+    assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="340" height="340"`))
+    assert.Equal(t, 64, strings.Count(svg, "<rect"))
+    assert.Equal(t, 32, strings.Count(svg, "<circle"))
+    assert.Contains(t, svg, ">a</text>")
+    assert.Contains(t, svg, ">8</text>")
+    assert.NotContains(t, svg, "<line")
+
+    assert.Nil(t, xml.Unmarshal([]byte(svg), new(interface{})))
+}
+
+func Test_SVG_FourPlayer(t *testing.T) {
+    game, err := NewSimpleFourPlayerGame()
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+
+    state.Pieces[0].D = true
+
+    svg := RenderSVG(state, &SVGOptions{
+        SquareSize: 20,
+        Arrows: []MoveKey{{XFrom: 3, YFrom: 12, XTo: 3, YTo: 10}},
+        Highlights: []SVGSquare{{3, 12}, {3, 10}},
+    })
+
+    assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="290" height="290"`))
+    assert.Equal(t, 14 * 14 + 2, strings.Count(svg, "<rect"))
+    assert.Equal(t, 36, strings.Count(svg, `fill="dimgray"`))
+    assert.Equal(t, 64, strings.Count(svg, "<circle"))
+    assert.Equal(t, 1, strings.Count(svg, `<g opacity="0.6">`))
+    assert.Contains(t, svg, `<line x1="80" y1="250" x2="80" y2="210"`)
+    assert.Contains(t, svg, ">n</text>")
+    assert.Contains(t, svg, ">14</text>")
+
+    assert.Nil(t, xml.Unmarshal([]byte(svg), new(interface{})))
+}
@@ -0,0 +1,99 @@
+package chess
+
+import "math/rand"
+
+/*
+Responsible for:
+- counting the checks each player received from every opponent in three-check games
+- eliminating players who were checked too often
+*/
+
+const DEFAULT_CHECK_LIMIT = 3
+
+// a player is out once they reach the limit, so one key for every count up to it covers every position
+func (s *SimplePlayerCollection) setCheckLimit(checkLimit int) {
+    s.checkLimit = checkLimit
+
+    s.zobristChecks = make([][][]uint64, s.players)
+    for checked := range s.zobristChecks {
+        s.zobristChecks[checked] = make([][]uint64, s.players)
+        for checker := range s.zobristChecks[checked] {
+            s.zobristChecks[checked][checker] = make([]uint64, checkLimit + 1)
+            for count := 1; count <= checkLimit; count++ {
+                s.zobristChecks[checked][checker][count] = rand.Uint64()
+            }
+        }
+    }
+}
+
+func (s *SimplePlayerCollection) addChecks(checker int, checked int, sign int) {
+    if s.colorOutOfBounds(checker) {
+        return
+    }
+
+    for color := 0; color < s.players; color++ {
+        if checked & (1 << color) != 0 {
+            s.checks[color][checker] += sign
+        }
+    }
+}
+
+// the first player still alive who received the check limit from one opponent, -1 when nobody did
+func (s *SimplePlayerCollection) checkedOut() int {
+    if s.checkLimit <= 0 {
+        return -1
+    }
+
+    for color := 0; color < s.players; color++ {
+        if !s.playersAlive[color] {
+            continue
+        }
+
+        for _, count := range s.checks[color] {
+            if count >= s.checkLimit {
+                return color
+            }
+        }
+    }
+
+    return -1
+}
+
+// checks received by each color from each opponent
+func (s *SimplePlayerCollection) checksState() [][]int {
+    checks := [][]int{}
+    if s.checkLimit <= 0 {
+        return checks
+    }
+
+    for _, received := range s.checks {
+        checks = append(checks, append([]int{}, received...))
+    }
+
+    return checks
+}
+
+// called after the move is executed and the moves are calculated, every opponent in check counts one check from the mover
+func (t *PlayerTransition) countChecks(b *SimpleBoard, move *FastMove) {
+    p := t.p
+    if p.checkLimit <= 0 || !p.playersAlive[move.color] {
+        return
+    }
+
+    checked := 0
+    for color := 0; color < p.getPlayers(); color++ {
+        if p.playersAlive[color] && !b.allied(color, move.color) && b.Check(color) {
+            checked |= 1 << color
+        }
+    }
+
+    t.checksColor = move.color
+    t.checked = checked
+}
+
+// a player checked too often is out like a player who resigned, the last team standing wins
+func createChecksTransition(b *SimpleBoard, p *SimplePlayerCollection, color int, t *PlayerTransition) {
+    createResignTransition(b, p, color, t)
+
+    t.resigned = false
+}
@@ -0,0 +1,147 @@
+package chess
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_ThreeCheck_Win(t *testing.T) {
+    white := 0
+
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "ThreeCheck": true,
+        "Pieces": [
+            {"X": 0, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 7, "Y": 6, "Color": 0, "Type": "R", "Moved": true},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true}
+        ]
+    }`)
+    p := game.getPlayerCollection()
+
+    for _, san := range []string{"Re2+", "Kd8", "Rd2+", "Kc8"} {
+        err := game.ExecuteSAN(san)
+        assert.Nil(t, err)
+    }
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, [][]int{{0, 0}, {2, 0}}, state.Checks)
+    hash := p.ZobristHash()
+
+    err = game.Undo()
+    assert.Nil(t, err)
+    err = game.Undo()
+    assert.Nil(t, err)
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, [][]int{{0, 0}, {1, 0}}, state.Checks)
+    assert.NotEqual(t, hash, p.ZobristHash())
+
+    err = game.Redo()
+    assert.Nil(t, err)
+    err = game.Redo()
+    assert.Nil(t, err)
+    assert.Equal(t, hash, p.ZobristHash())
+
+    err = game.ExecuteSAN("Rc2+")
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, white, state.WinningPlayer)
+    assert.Equal(t, [][]int{{0, 0}, {3, 0}}, state.Checks)
+}
+
+func Test_ThreeCheck_ZobristHash(t *testing.T) {
+    white := 0
+    black := 1
+    red := 2
+
+    p, err := newSimplePlayerCollection(4)
+    assert.Nil(t, err)
+    p.setCheckLimit(DEFAULT_CHECK_LIMIT)
+
+    // every count from every checker up to the limit hashes differently
+    hashes := map[uint64]bool{}
+    for blackChecks := 0; blackChecks <= DEFAULT_CHECK_LIMIT; blackChecks++ {
+        for redChecks := 0; redChecks <= DEFAULT_CHECK_LIMIT; redChecks++ {
+            p.checks[white][black] = blackChecks
+            p.checks[white][red] = redChecks
+            hashes[p.ZobristHash()] = true
+        }
+    }
+    assert.Equal(t, 16, len(hashes))
+
+    copied, err := p.Copy()
+    assert.Nil(t, err)
+    assert.Equal(t, DEFAULT_CHECK_LIMIT, copied.checkLimit)
+    assert.Equal(t, p.checks, copied.checks)
+    assert.NotPanics(t, func() { copied.ZobristHash() })
+}
+
+func Test_ThreeCheck_FourPlayer(t *testing.T) {
+    white := 0
+    red := 1
+    blue := 2
+
+    game := newVariantGame(t, `{
+        "Width": 6,
+        "Height": 6,
+        "Players": 4,
+        "ThreeCheck": true,
+        "Checks": 1,
+        "Pieces": [
+            {"X": 5, "Y": 5, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 2, "Y": 5, "Color": 0, "Type": "R", "Moved": true},
+            {"X": 0, "Y": 3, "Color": 1, "Type": "K", "Orientation": "R", "Moved": true},
+            {"X": 5, "Y": 0, "Color": 2, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 3, "Y": 0, "Color": 3, "Type": "K", "Orientation": "L", "Moved": true}
+        ]
+    }`)
+    p := game.getPlayerCollection()
+
+    // the rook checks red, who is out after a single check
+    err := game.Execute(2, 5, 2, 3, "")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.False(t, state.GameOver)
+    assert.Equal(t, blue, state.CurrentPlayer)
+    assert.False(t, p.playersAlive[red])
+    assert.Equal(t, 1, state.Checks[red][white])
+    assert.Equal(t, 0, state.Checks[red][blue])
+
+    err = game.Undo()
+    assert.Nil(t, err)
+    assert.True(t, p.playersAlive[red])
+    assert.Equal(t, 0, p.checks[red][white])
+}
+
+func Test_ThreeCheck_Searcher(t *testing.T) {
+    game := newVariantGame(t, `{
+        "Width": 8,
+        "Height": 8,
+        "Players": 2,
+        "ThreeCheck": true,
+        "Checks": 1,
+        "Pieces": [
+            {"X": 0, "Y": 7, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 2, "Color": 0, "Type": "N"},
+            {"X": 4, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 1, "Y": 4, "Color": 1, "Type": "Q"}
+        ]
+    }`)
+
+    // the knight checks instead of capturing the queen
+    stop := make(chan bool)
+    searcher := newParallelSearcher(game.getBoard(), game.getPlayerCollection(), stop)
+    moveKey, err := searcher.searchWithMinimax(2)
+    assert.Nil(t, err)
+    assert.Equal(t, MoveKey{XFrom: 0, YFrom: 2, XTo: 2, YTo: 1}, moveKey)
+}
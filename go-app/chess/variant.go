@@ -0,0 +1,327 @@
+package chess
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+/*
+Responsible for:
+- reading board layouts from variant definitions
+- validating variant definitions
+*/
+type VariantDefinition struct {
+    Name string
+    Width int
+    Height int
+    Players int
+    TurnOrder []int // colors in the order they move, defaults to 0, 1, ...
+    Teams []int // team of each color, teammates can't capture each other and win together
+    Points *PointsRules // free for all scoring, the player with the most points wins
+    Crazyhouse bool // captured pieces go into the capturer's reserve and can be dropped on empty squares
+    Bughouse bool // crazyhouse where captured pieces go to the partner on a linked board, two players only
+    KingOfTheHill bool // a king reaching one of the hill squares wins
+    Hill []VariantSquare // defaults to the center squares, 2x2 on boards with an even size
+    ThreeCheck bool // a player checked Checks times by one opponent is out, the last player standing wins
+    Checks int // checks that put a player out in three-check, defaults to 3
+    Promotions []string // letters of the pieces pawns promote to, defaults to Q, R, B, and N
+    Atomic bool // captures explode the capturer and the pieces next to the captured square except pawns, exploding a king wins
+    EliminatedPieces string // walls, remove, zombies, or random, defaults to walls
+    ZombiePoints int // points for capturing a piece of an eliminated player with zombies or random
+    HalfMoveLimit int // plies without a capture or pawn move before a draw, defaults to fifty moves for each player
+    Chess960 bool // shuffles the back rank of every color into the same chess960 starting position
+    Chess960Position int // 0 to 959, negative picks a random position
+    Disabled []VariantSquare
+    Pieces []VariantPiece
+}
+
+var eliminatedPiecesNames = map[string]int{
+    "": ELIMINATED_WALLS,
+    "walls": ELIMINATED_WALLS,
+    "remove": ELIMINATED_REMOVED,
+    "zombies": ELIMINATED_ZOMBIES,
+    "random": ELIMINATED_RANDOM,
+}
+
+type VariantSquare struct {
+    X int
+    Y int
+}
+
+type VariantPiece struct {
+    X int
+    Y int
+    Color int
+    Type string // P, N, B, R, Q, K, or a fairy piece: A, C, M, L, Z, H, or G
+    Orientation string // U, D, L, or R for pawns and kings
+    Moved bool // moved pawns can't double step, moved rooks and kings can't castle
+}
+
+func ParseVariant(data []byte) (*VariantDefinition, error) {
+    definition := &VariantDefinition{}
+
+    err := json.Unmarshal(data, definition)
+    if err != nil {
+        return nil, fmt.Errorf("invalid variant definition: %v", err)
+    }
+
+    return definition, nil
+}
+
+func NewGameFromVariant(definition *VariantDefinition) (Game, error) {
+    b, p, err := createSimpleBoardFromVariant(definition)
+    if err != nil {
+        return nil, err
+    }
+
+    i, err := invokerFactoryInstance.newSimpleInvoker()
+    if err != nil {
+        return nil, err
+    }
+
+    return &SimpleGame{
+        b: b,
+        p: p,
+        i: i,
+    }, nil
+}
+
+func createSimpleBoardFromVariant(definition *VariantDefinition) (*SimpleBoard, *SimplePlayerCollection, error) {
+    err := definition.Validate()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    b, err := newSimpleBoard(definition.Width, definition.Height, definition.Players)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    for _, piece := range definition.Pieces {
+        b.setPiece(b.getIndex(piece.X, piece.Y), b.getAllPiece(piece.Color, piece.index()))
+    }
+
+    for _, square := range definition.Disabled {
+        b.disableLocation(b.getIndex(square.X, square.Y))
+    }
+
+    if definition.Chess960 {
+        err = shuffleChess960BackRanks(b, definition.Chess960Position)
+        if err != nil {
+            return nil, nil, err
+        }
+    }
+
+    p, err := newSimplePlayerCollection(definition.Players)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    if len(definition.TurnOrder) > 0 {
+        p.setTurnOrder(definition.TurnOrder)
+    }
+
+    if len(definition.Teams) > 0 {
+        b.setTeams(definition.Teams)
+        p.setTeams(definition.Teams)
+    }
+
+    if definition.Points != nil {
+        p.setPointsRules(definition.Points)
+    }
+
+    p.setEliminatedPieces(eliminatedPiecesNames[definition.EliminatedPieces], definition.ZombiePoints)
+    p.setHalfMoveLimit(definition.HalfMoveLimit)
+
+    if definition.ThreeCheck && definition.Checks > 0 {
+        p.setCheckLimit(definition.Checks)
+    } else if definition.ThreeCheck {
+        p.setCheckLimit(DEFAULT_CHECK_LIMIT)
+    }
+
+    b.atomic = definition.Atomic
+
+    if len(definition.Promotions) > 0 {
+        b.promotions = []int{}
+        for _, name := range definition.Promotions {
+            b.promotions = append(b.promotions, promotionIndex(name))
+        }
+    }
+
+    if definition.KingOfTheHill && len(definition.Hill) > 0 {
+        b.setHill(definition.Hill)
+    } else if definition.KingOfTheHill {
+        b.setHill(centerSquares(definition.Width, definition.Height))
+    }
+
+    if definition.Crazyhouse || definition.Bughouse {
+        b.setCrazyhouse()
+        b.bughouse = definition.Bughouse
+    }
+
+    b.populatePieceSquareTables()
+    b.CalculateMoves()
+
+    return b, p, nil
+}
+
+func (d *VariantDefinition) Validate() error {
+    if d.Width <= 0 || d.Height <= 0 {
+        return fmt.Errorf("invalid variant board size")
+    }
+
+    if d.Players <= 0 {
+        return fmt.Errorf("invalid variant number of players")
+    }
+
+    if d.HalfMoveLimit < 0 {
+        return fmt.Errorf("invalid variant half move limit")
+    }
+
+    if _, ok := eliminatedPiecesNames[d.EliminatedPieces]; !ok {
+        return fmt.Errorf("invalid variant eliminated pieces %s", d.EliminatedPieces)
+    }
+
+    if d.Checks < 0 {
+        return fmt.Errorf("invalid variant checks")
+    }
+
+    if d.ZombiePoints < 0 {
+        return fmt.Errorf("invalid variant zombie points")
+    }
+
+    // without points zombies would be the same as walls
+    if eliminatedPiecesNames[d.EliminatedPieces] == ELIMINATED_ZOMBIES && d.Points == nil {
+        return fmt.Errorf("invalid variant zombies need points")
+    }
+
+    if d.Bughouse && d.Players != 2 {
+        return fmt.Errorf("invalid variant bughouse needs two players")
+    }
+
+    if d.Chess960Position >= CHESS960_POSITIONS {
+        return fmt.Errorf("invalid variant chess960 position")
+    }
+
+    if len(d.TurnOrder) > 0 {
+        if len(d.TurnOrder) != d.Players {
+            return fmt.Errorf("invalid variant turn order length")
+        }
+
+        seen := make([]bool, d.Players)
+        for _, color := range d.TurnOrder {
+            if color < 0 || color >= d.Players || seen[color] {
+                return fmt.Errorf("invalid variant turn order")
+            }
+            seen[color] = true
+        }
+    }
+
+    if len(d.Teams) > 0 {
+        if len(d.Teams) != d.Players {
+            return fmt.Errorf("invalid variant teams length")
+        }
+
+        for _, team := range d.Teams {
+            if team < 0 || team >= d.Players {
+                return fmt.Errorf("invalid variant team %d", team)
+            }
+        }
+    }
+
+    if d.Points != nil {
+        err := d.Points.Validate()
+        if err != nil {
+            return err
+        }
+    }
+
+    occupied := make([][]bool, d.Height)
+    for y := range occupied {
+        occupied[y] = make([]bool, d.Width)
+    }
+
+    for _, square := range d.Disabled {
+        if square.X < 0 || square.X >= d.Width || square.Y < 0 || square.Y >= d.Height {
+            return fmt.Errorf("invalid variant disabled square %d,%d", square.X, square.Y)
+        }
+
+        if occupied[square.Y][square.X] {
+            return fmt.Errorf("duplicate variant disabled square %d,%d", square.X, square.Y)
+        }
+        occupied[square.Y][square.X] = true
+    }
+
+    // the default hill is checked like an explicit one, a disabled center square can't be part of it
+    hill := d.Hill
+    if d.KingOfTheHill && len(hill) == 0 {
+        hill = centerSquares(d.Width, d.Height)
+    }
+
+    for _, square := range hill {
+        if square.X < 0 || square.X >= d.Width || square.Y < 0 || square.Y >= d.Height || occupied[square.Y][square.X] {
+            return fmt.Errorf("invalid variant hill square %d,%d", square.X, square.Y)
+        }
+    }
+
+    promotions := map[int]bool{}
+    for _, name := range d.Promotions {
+        index := promotionIndex(name)
+        if index < 0 || promotions[index] {
+            return fmt.Errorf("invalid variant promotion %s", name)
+        }
+        promotions[index] = true
+    }
+
+    kings := make([]int, d.Players)
+    for _, piece := range d.Pieces {
+        if piece.X < 0 || piece.X >= d.Width || piece.Y < 0 || piece.Y >= d.Height {
+            return fmt.Errorf("invalid variant piece square %d,%d", piece.X, piece.Y)
+        }
+
+        if occupied[piece.Y][piece.X] {
+            return fmt.Errorf("variant piece on occupied or disabled square %d,%d", piece.X, piece.Y)
+        }
+        occupied[piece.Y][piece.X] = true
+
+        if piece.Color < 0 || piece.Color >= d.Players {
+            return fmt.Errorf("invalid variant piece color %d", piece.Color)
+        }
+
+        index := piece.index()
+        if index < 0 {
+            return fmt.Errorf("invalid variant piece %s%s at %d,%d", piece.Type, piece.Orientation, piece.X, piece.Y)
+        }
+
+        if (&Piece{piece.Color, index}).isKing() {
+            kings[piece.Color]++
+        }
+    }
+
+    for color, count := range kings {
+        if count != 1 {
+            return fmt.Errorf("variant color %d needs exactly one king", color)
+        }
+    }
+
+    return nil
+}
+
+// promoted rooks count as moved so they can't castle, pawns and kings aren't promotions
+func promotionIndex(name string) int {
+    index := pieceIndexFromName(name)
+    if index < 0 || piece_types[index].pawn || piece_types[index].king {
+        return -1
+    }
+
+    return piece_types[index].movedIndex
+}
+
+func (v *VariantPiece) index() int {
+    code := v.Type + v.Orientation
+    if v.Moved {
+        code += "m"
+    }
+
+    return pieceIndexFromCode(code)
+}
@@ -0,0 +1,433 @@
+package chess
+
+import (
+    "math/rand"
+    "os"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_Variant_BuiltInLayouts(t *testing.T) {
+    for name, newGame := range map[string]func() (Game, error){
+        "standard": NewSimpleGame,
+        "small": NewSimpleSmallGame,
+        "four": NewSimpleFourPlayerGame,
+        "smallfour": NewSimpleSmallFourPlayerGame,
+    } {
+        data, err := os.ReadFile("../variants/" + name + ".json")
+        assert.Nil(t, err, name)
+
+        definition, err := ParseVariant(data)
+        assert.Nil(t, err, name)
+
+        game, err := NewGameFromVariant(definition)
+        assert.Nil(t, err, name)
+
+        expectedGame, err := newGame()
+        assert.Nil(t, err, name)
+
+        gfen, err := game.GFEN()
+        assert.Nil(t, err, name)
+        expectedGFEN, err := expectedGame.GFEN()
+        assert.Nil(t, err, name)
+        assert.Equal(t, expectedGFEN, gfen, name)
+    }
+}
+
+func Test_Variant_Files(t *testing.T) {
+    files, err := os.ReadDir("../variants")
+    assert.Nil(t, err)
+
+    for _, file := range files {
+        data, err := os.ReadFile("../variants/" + file.Name())
+        assert.Nil(t, err, file.Name())
+
+        definition, err := ParseVariant(data)
+        assert.Nil(t, err, file.Name())
+
+        _, err = NewGameFromVariant(definition)
+        assert.Nil(t, err, file.Name())
+    }
+}
+
+func Test_Variant_TurnOrder(t *testing.T) {
+    white := 0
+    black := 1
+
+    definition, err := ParseVariant([]byte(`{
+        "Width": 3,
+        "Height": 4,
+        "Players": 2,
+        "TurnOrder": [1, 0],
+        "Pieces": [
+            {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 1, "Y": 1, "Color": 1, "Type": "P", "Orientation": "D"},
+            {"X": 2, "Y": 3, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true}
+        ]
+    }`))
+    assert.Nil(t, err)
+
+    game, err := NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, black, state.CurrentPlayer)
+
+    err = game.ExecuteSAN("b2")
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, white, state.CurrentPlayer)
+
+    err = game.ExecuteSAN("Kb2")
+    assert.Nil(t, err)
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn, "1. b2+ Kxb2 1/2-1/2")
+
+    gameCopy, err := game.Copy()
+    assert.Nil(t, err)
+
+    next, _ := gameCopy.getPlayerCollection().getNextAndRemaining()
+    assert.Equal(t, white, next)
+    assert.Equal(t, black, gameCopy.getPlayerCollection().incrementOnce(white))
+}
+
+func Test_Variant_Invalid(t *testing.T) {
+    _, err := ParseVariant([]byte(`{"Width": "eight"}`))
+    assert.NotNil(t, err)
+
+    kings := []VariantPiece{
+        {X: 0, Y: 0, Color: 0, Type: "K", Orientation: "U"},
+        {X: 3, Y: 3, Color: 1, Type: "K", Orientation: "D"},
+    }
+
+    for _, definition := range []VariantDefinition{
+        {Width: 0, Height: 4, Players: 2, Pieces: kings},
+        {Width: 4, Height: 4, Players: 0, Pieces: kings},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, TurnOrder: []int{0}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, TurnOrder: []int{1, 1}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, TurnOrder: []int{0, 2}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Teams: []int{0}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Teams: []int{0, 2}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Points: &PointsRules{Checkmate: -1}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Disabled: []VariantSquare{{4, 0}}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Disabled: []VariantSquare{{1, 0}, {1, 0}}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Disabled: []VariantSquare{{0, 0}}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Promotions: []string{"K"}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Promotions: []string{"P"}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Promotions: []string{"X"}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings, Promotions: []string{"Q", "Q"}},
+        {Width: 4, Height: 4, Players: 2, Pieces: kings[:1]},
+        {Width: 4, Height: 4, Players: 2, Pieces: append([]VariantPiece{{X: 0, Y: 0, Color: 0, Type: "Q"}}, kings...)},
+        {Width: 4, Height: 4, Players: 2, Pieces: append([]VariantPiece{{X: 1, Y: 0, Color: 2, Type: "Q"}}, kings...)},
+        {Width: 4, Height: 4, Players: 2, Pieces: append([]VariantPiece{{X: 1, Y: 0, Color: 0, Type: "X"}}, kings...)},
+        {Width: 4, Height: 4, Players: 2, Pieces: append([]VariantPiece{{X: 1, Y: 0, Color: 0, Type: "P"}}, kings...)},
+        {Width: 4, Height: 4, Players: 2, Pieces: append([]VariantPiece{{X: 1, Y: 0, Color: 0, Type: "N", Moved: true}}, kings...)},
+        {Width: 4, Height: 4, Players: 2, Pieces: append([]VariantPiece{{X: 1, Y: 0, Color: 0, Type: "K", Orientation: "U"}}, kings...)},
+    } {
+        _, err := NewGameFromVariant(&definition)
+        assert.NotNil(t, err, definition)
+    }
+}
+
+func Test_Variant_HalfMoveLimit(t *testing.T) {
+    definition, err := ParseVariant([]byte(`{
+        "Width": 6,
+        "Height": 6,
+        "Players": 4,
+        "HalfMoveLimit": 4,
+        "Pieces": [
+            {"X": 0, "Y": 5, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 2, "Y": 3, "Color": 0, "Type": "N"},
+            {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "R", "Moved": true},
+            {"X": 5, "Y": 0, "Color": 2, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 5, "Y": 5, "Color": 3, "Type": "K", "Orientation": "L", "Moved": true}
+        ]
+    }`))
+    assert.Nil(t, err)
+
+    game, err := NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    for _, move := range [][]int{{0, 5, 1, 5}, {0, 0, 0, 1}, {5, 0, 4, 0}} {
+        err = game.Execute(move[0], move[1], move[2], move[3], "")
+        assert.Nil(t, err)
+    }
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Execute(5, 5, 5, 4, "")
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 4, state.HalfMoveClock)
+    assert.Equal(t, true, state.GameOver)
+    assert.Equal(t, -1, state.WinningPlayer)
+
+    definition.HalfMoveLimit = -1
+    assert.NotNil(t, definition.Validate())
+}
+
+func Test_Variant_Teams(t *testing.T) {
+    white := 0
+    black := 1
+    gray := 2
+    blue := 3
+
+    definition, err := ParseVariant([]byte(`{
+        "Width": 6,
+        "Height": 6,
+        "Players": 4,
+        "Teams": [0, 1, 0, 1],
+        "Pieces": [
+            {"X": 5, "Y": 5, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+            {"X": 0, "Y": 4, "Color": 0, "Type": "R"},
+            {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "R", "Moved": true},
+            {"X": 5, "Y": 0, "Color": 2, "Type": "K", "Orientation": "D", "Moved": true},
+            {"X": 0, "Y": 2, "Color": 2, "Type": "N"},
+            {"X": 3, "Y": 3, "Color": 3, "Type": "K", "Orientation": "L", "Moved": true}
+        ]
+    }`))
+    assert.Nil(t, err)
+
+    game, err := NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    err = game.Execute(0, 4, 0, 2, "") // white rook can't capture the gray knight
+    assert.NotNil(t, err)
+    err = game.Execute(0, 4, 0, 3, "")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []int{0, 1, 0, 1}, state.Teams)
+    assert.Equal(t, -1, state.WinningTeam)
+
+    err = game.Resign(black)
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, gray, state.CurrentPlayer)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Resign(white)
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, false, state.GameOver)
+
+    err = game.Resign(blue)
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, true, state.GameOver)
+    assert.Equal(t, gray, state.WinningPlayer)
+    assert.Equal(t, 0, state.WinningTeam)
+    assert.Equal(t, "1-0-1-0", pgn4Result(game.getPlayerCollection()))
+}
+
+func Test_Variant_Capablanca(t *testing.T) {
+    white := 0
+
+    data, err := os.ReadFile("../variants/capablanca.json")
+    assert.Nil(t, err)
+
+    definition, err := ParseVariant(data)
+    assert.Nil(t, err)
+
+    game, err := NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "rnabqkbcnr/pppppppppp/10/10/10/10/PPPPPPPPPP/RNABQKBCNR w KQkq - 0 1", fen)
+
+    moves, err := game.Moves(white)
+    assert.Nil(t, err)
+    assert.Equal(t, 28, len(moves)) // 20 pawn moves, 4 knight moves, and 4 archbishop and chancellor moves
+
+    game, err = NewSimpleGameFromFEN("rnabqkbcnr/pppppppppp/10/10/10/10/PPPPPPPPPP/RNABQK3R w KQkq - 0 1")
+    assert.Nil(t, err)
+
+    err = game.ExecuteSAN("O-O")
+    assert.Nil(t, err)
+
+    b := game.getBoard()
+    assert.Equal(t, b.getAllPiece(white, KING_U_M), b.getPiece(b.getIndex(8, 7)))
+    assert.Equal(t, b.getAllPiece(white, ROOK_M), b.getPiece(b.getIndex(7, 7)))
+
+    err = game.ExecuteSAN("Ci6")
+    assert.Nil(t, err)
+
+    pgn, err := game.PGN()
+    assert.Nil(t, err)
+    assert.Contains(t, pgn, "1. O-O Ci6 *")
+
+    // pawns also promote to the chancellor and the archbishop
+    definition.Pieces = []VariantPiece{
+        {X: 5, Y: 7, Color: white, Type: "K", Orientation: "U", Moved: true},
+        {X: 5, Y: 0, Color: 1, Type: "K", Orientation: "D", Moved: true},
+        {X: 0, Y: 1, Color: white, Type: "P", Orientation: "U", Moved: true},
+    }
+    game, err = NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    moves, err = game.Moves(white)
+    assert.Nil(t, err)
+    promotions := []string{}
+    for _, moveKey := range moves {
+        if moveKey.Promotion != "" {
+            promotions = append(promotions, moveKey.Promotion)
+        }
+    }
+    assert.Equal(t, []string{"Q", "C", "A", "R", "B", "N"}, promotions)
+
+    err = game.Execute(0, 1, 0, 0, "C")
+    assert.Nil(t, err)
+
+    b = game.getBoard()
+    assert.Equal(t, b.getAllPiece(white, CHANCELLOR), b.getPiece(b.getIndex(0, 0)))
+}
+
+func Test_Variant_Grand(t *testing.T) {
+    white := 0
+
+    data, err := os.ReadFile("../variants/grand.json")
+    assert.Nil(t, err)
+
+    definition, err := ParseVariant(data)
+    assert.Nil(t, err)
+
+    game, err := NewGameFromVariant(definition)
+    assert.Nil(t, err)
+
+    fen, err := game.FEN()
+    assert.Nil(t, err)
+    assert.Equal(t, "r8r/1nbqkcabn1/pppppppppp/10/10/10/10/PPPPPPPPPP/1NBQKCABN1/R8R w - - 0 1", fen)
+
+    moves, err := game.Moves(white)
+    assert.Nil(t, err)
+    assert.Equal(t, 65, len(moves)) // the rooks move along the empty first rank
+}
+
+func Test_Variant_EliminatedPieces(t *testing.T) {
+    white := 0
+    black := 1
+    gray := 2
+
+    newGame := func(eliminatedPieces string) Game {
+        definition, err := ParseVariant([]byte(`{
+            "Width": 6,
+            "Height": 6,
+            "Players": 4,
+            "Points": {"Captures": {"N": 3}},
+            "ZombiePoints": 1,
+            "Pieces": [
+                {"X": 5, "Y": 5, "Color": 0, "Type": "K", "Orientation": "U", "Moved": true},
+                {"X": 1, "Y": 4, "Color": 0, "Type": "R"},
+                {"X": 0, "Y": 0, "Color": 1, "Type": "K", "Orientation": "R", "Moved": true},
+                {"X": 1, "Y": 2, "Color": 1, "Type": "N"},
+                {"X": 5, "Y": 0, "Color": 2, "Type": "K", "Orientation": "D", "Moved": true},
+                {"X": 3, "Y": 3, "Color": 3, "Type": "K", "Orientation": "L", "Moved": true}
+            ]
+        }`))
+        assert.Nil(t, err)
+        definition.EliminatedPieces = eliminatedPieces
+
+        game, err := NewGameFromVariant(definition)
+        assert.Nil(t, err)
+
+        err = game.Execute(5, 5, 5, 4, "")
+        assert.Nil(t, err)
+        err = game.Resign(black)
+        assert.Nil(t, err)
+
+        return game
+    }
+
+    // walls stay on the board and are worth nothing
+    game := newGame("walls")
+    err := game.Execute(5, 0, 4, 0, "")
+    assert.Nil(t, err)
+    err = game.Execute(3, 3, 3, 2, "")
+    assert.Nil(t, err)
+    err = game.Execute(1, 4, 1, 2, "")
+    assert.Nil(t, err)
+
+    state, err := game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 0, state.Points[white])
+
+    // removed pieces come back on undo
+    game = newGame("remove")
+    b := game.getBoard()
+    assert.Nil(t, b.getPiece(b.getIndex(0, 0)))
+    assert.Nil(t, b.getPiece(b.getIndex(1, 2)))
+
+    err = game.Undo()
+    assert.Nil(t, err)
+    assert.Equal(t, b.getAllPiece(black, KNIGHT), b.getPiece(b.getIndex(1, 2)))
+    assert.Equal(t, true, game.getPlayerCollection().playersAlive[black])
+
+    err = game.Redo()
+    assert.Nil(t, err)
+    assert.Nil(t, b.getPiece(b.getIndex(1, 2)))
+
+    // zombies are worth the zombie points
+    game = newGame("zombies")
+    err = game.Execute(5, 0, 4, 0, "")
+    assert.Nil(t, err)
+    err = game.Execute(3, 3, 3, 2, "")
+    assert.Nil(t, err)
+    err = game.Execute(1, 4, 1, 2, "")
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, 1, state.Points[white])
+
+    // random pieces keep their turn after the player is eliminated
+    randomGame := func() Game {
+        game := newGame("random")
+        game.(*SimpleGame).random = rand.New(rand.NewSource(1))
+
+        err := game.Execute(5, 0, 4, 0, "")
+        assert.Nil(t, err)
+        err = game.Execute(3, 3, 3, 2, "")
+        assert.Nil(t, err)
+        err = game.Execute(1, 4, 1, 3, "")
+        assert.Nil(t, err)
+
+        return game
+    }
+    game = randomGame()
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, gray, state.CurrentPlayer)
+    assert.Equal(t, 6, game.HistoryLength())
+    assert.Equal(t, randomGame().Print(), game.Print()) // the same seed makes the same random move
+
+    err = game.Undo()
+    assert.Nil(t, err)
+
+    state, err = game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, white, state.CurrentPlayer)
+
+    _, err = ParseVariant([]byte(`{"EliminatedPieces": "ghosts"}`))
+    assert.Nil(t, err)
+    assert.NotNil(t, (&VariantDefinition{Width: 4, Height: 4, Players: 2, EliminatedPieces: "ghosts"}).Validate())
+    assert.NotNil(t, (&VariantDefinition{Width: 4, Height: 4, Players: 2, EliminatedPieces: "zombies"}).Validate())
+}
@@ -119,16 +119,20 @@ func (c *PlayerClient) writeLoop() {
     }
 }
 
-func newBotClient(hub *Hub, game chess.Game) (*BotClient, error) {
-    bot, err := chess.NewSimpleBot(game, 20, 5)
-    if err != nil {
-        return nil, err
-    }
+const botDepthLimit = 20
+const botTimeLimit = 5 * time.Second // used when the game is untimed
+
+// the bot searches a copy of the game taken in the hub goroutine, the hub keeps changing its own game
+type BotRequest struct {
+    game chess.Game
+    timeLimit time.Duration
+    historyLength int // the hub drops the move once its game moved past the searched position
+}
 
+func newBotClient(hub *Hub) (*BotClient, error) {
     botClient := &BotClient{
         hub: hub,
-        bot: bot,
-        send: make(chan []byte, 256),
+        requests: make(chan *BotRequest, 256),
     }
 
     return botClient, nil
@@ -136,13 +140,28 @@ func newBotClient(hub *Hub, game chess.Game) (*BotClient, error) {
 
 type BotClient struct {
     hub *Hub
-    bot chess.Bot
-    send chan []byte
+    requests chan *BotRequest
 }
 
-func (c *BotClient) sendMessage(message []byte) error {
+// called by the hub goroutine for every broadcast, only a new board state can need a move
+func (c *BotClient) sendMessage(unmarshalledMessage []byte) error {
+    var message *Message
+    err := json.Unmarshal(unmarshalledMessage, &message)
+    if err != nil || message.Type != "BoardState" {
+        return nil
+    }
+
+    request, err := c.hub.createBotRequest()
+    if err != nil {
+        return err
+    }
+
+    if request == nil {
+        return nil
+    }
+
     select {
-    case c.send <- message:
+    case c.requests <- request:
     default:
         c.hub.unregister <- c
     }
@@ -150,7 +169,7 @@ func (c *BotClient) sendMessage(message []byte) error {
 }
 
 func (c *BotClient) close() error {
-    close(c.send)
+    close(c.requests)
     return nil
 }
 
@@ -159,63 +178,47 @@ func (c *BotClient) run() {
         fmt.Println("ending run")
         c.hub.unregister <- c
     }()
-    for {
-        select {
-        case unmarshalledMessage, ok := <-c.send:
-            if !ok {
-                return
-            }
+    for request := range c.requests {
+        bot, err := chess.NewSimpleBotWithTimeLimit(request.game, botDepthLimit, request.timeLimit)
+        if err != nil {
+            fmt.Println("error creating bot")
+            continue
+        }
 
-            var message *Message
-            err := json.Unmarshal(unmarshalledMessage, &message)
-            if err != nil {
-                continue
-            }
+        moveKey, err := bot.FindMoveIterativeDeepening()
+        if err != nil {
+            fmt.Println("error finding move")
+            continue
+        }
 
-            if message.Type != "BoardState" {
-                continue
-            }
+        marshalledMoveKey, err := json.Marshal(MoveData{
+            XFrom: moveKey.XFrom,
+            YFrom: moveKey.YFrom,
+            XTo: moveKey.XTo,
+            YTo: moveKey.YTo,
+            Promotion: moveKey.Promotion,
+            Drop: moveKey.Drop,
+            HistoryLength: &request.historyLength,
+        })
+        if err != nil {
+            fmt.Println("error marshalling moveKey")
+            continue
+        }
 
-            var boardData *chess.BoardData
-            err = json.Unmarshal(message.Data, &boardData)
-            if err != nil {
-                continue
-            }
+        message := Message{
+            Type: "move",
+            Data: marshalledMoveKey,
+        }
 
-            for _, color := range c.hub.botColors {
-                if !boardData.GameOver && color == boardData.CurrentPlayer {
-                    moveKey, err := c.bot.FindMoveIterativeDeepening()
-                    if err != nil {
-                        fmt.Println("error finding move")
-                        break
-                    }
-
-                    marshalledMoveKey, err := json.Marshal(moveKey)
-                    if err != nil {
-                        fmt.Println("error marshalling moveKey")
-                        break
-                    }
-
-                    message := Message{
-                        Type: "move",
-                        Data: marshalledMoveKey,
-                    }
-
-                    marshalledMessage, err := json.Marshal(message)
-                    if err != nil {
-                        fmt.Println("error marshalling moveKey")
-                        break
-                    }
-
-                    c.hub.send <- &ClientMessage{
-                        message: marshalledMessage,
-                        client: c,
-                    }
-
-                    break
-                }
-            }
+        marshalledMessage, err := json.Marshal(message)
+        if err != nil {
+            fmt.Println("error marshalling moveKey")
+            continue
+        }
+
+        c.hub.send <- &ClientMessage{
+            message: marshalledMessage,
+            client: c,
         }
     }
 }
-
@@ -0,0 +1,193 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+const clockTickPeriod = 100 * time.Millisecond
+const botMovesToGo = 30 // the bot plans its remaining time for this many moves
+const botMinTimeLimit = 100 * time.Millisecond
+
+type TimeControl struct {
+    Base time.Duration
+    Increment time.Duration // fischer, added after every move
+    Delay time.Duration // bronstein, time used up to the delay is given back after every move
+    MoveTime time.Duration // every move gets this fixed time, base, increment, and delay are ignored
+}
+
+type ClockData struct {
+    Remaining []int64 // milliseconds left for each color
+    Running int // color whose clock is running, -1 when stopped
+}
+
+func newClock(timeControl TimeControl, players int) *Clock {
+    remaining := make([]time.Duration, players)
+    for color := range remaining {
+        remaining[color] = timeControl.Base
+        if timeControl.MoveTime > 0 {
+            remaining[color] = timeControl.MoveTime
+        }
+    }
+
+    clock := &Clock{
+        timeControl: timeControl,
+        remaining: remaining,
+        running: -1,
+        snapshots: map[int][]time.Duration{},
+    }
+    clock.save(0)
+
+    return clock
+}
+
+// the clock doesn't run until the first move is made
+type Clock struct {
+    timeControl TimeControl
+    remaining []time.Duration
+    running int
+    turnStart time.Time
+    snapshots map[int][]time.Duration // remaining times by the length of the game history
+}
+
+// the color finished their move, the next color's clock starts
+func (c *Clock) press(color int, next int, now time.Time) {
+    used := time.Duration(0)
+    if c.running == color {
+        used = now.Sub(c.turnStart)
+        c.remaining[color] -= used
+    }
+
+    if c.timeControl.MoveTime > 0 {
+        c.remaining[color] = c.timeControl.MoveTime
+    } else {
+        c.remaining[color] += c.timeControl.Increment + min(used, c.timeControl.Delay)
+    }
+
+    c.running = next
+    c.turnStart = now
+}
+
+// switches the running clock without giving any time back, used when a player is eliminated
+func (c *Clock) start(color int, now time.Time) {
+    if c.running >= 0 && c.running != color {
+        c.remaining[c.running] -= now.Sub(c.turnStart)
+    }
+
+    if c.running != color {
+        c.turnStart = now
+    }
+    c.running = color
+}
+
+func (c *Clock) stop(now time.Time) {
+    if c.running >= 0 {
+        c.remaining[c.running] -= now.Sub(c.turnStart)
+    }
+
+    c.running = -1
+}
+
+// the color that ran out of time, or -1
+func (c *Clock) flagged(now time.Time) int {
+    if c.running < 0 {
+        return -1
+    }
+
+    if c.remaining[c.running] - now.Sub(c.turnStart) <= 0 {
+        return c.running
+    }
+
+    return -1
+}
+
+// a share of the remaining time plus what the next move gives back, leaving a margin to send the move
+func (c *Clock) botTimeLimit(color int, now time.Time) time.Duration {
+    remaining := c.remaining[color]
+    if color == c.running {
+        remaining -= now.Sub(c.turnStart)
+    }
+
+    limit := remaining / botMovesToGo + c.timeControl.Increment + c.timeControl.Delay
+    if c.timeControl.MoveTime > 0 {
+        limit = remaining
+    }
+
+    return max(min(limit, remaining * 9 / 10), botMinTimeLimit)
+}
+
+func (c *Clock) save(historyLength int) {
+    for key := range c.snapshots {
+        if key > historyLength {
+            delete(c.snapshots, key)
+        }
+    }
+
+    c.snapshots[historyLength] = append([]time.Duration{}, c.remaining...)
+}
+
+// restores the remaining times of a position in the history, used for undo and redo
+func (c *Clock) restore(historyLength int, running int, now time.Time) error {
+    snapshot, ok := c.snapshots[historyLength]
+    if !ok {
+        return fmt.Errorf("no clock state for history length %d", historyLength)
+    }
+
+    copy(c.remaining, snapshot)
+    c.running = -1
+    if historyLength > 0 {
+        c.running = running
+        c.turnStart = now
+    }
+
+    return nil
+}
+
+func (c *Clock) data(now time.Time) *ClockData {
+    remaining := make([]int64, len(c.remaining))
+    for color, duration := range c.remaining {
+        if color == c.running {
+            duration -= now.Sub(c.turnStart)
+        }
+
+        remaining[color] = max(duration.Milliseconds(), 0)
+    }
+
+    return &ClockData{
+        Remaining: remaining,
+        Running: c.running,
+    }
+}
+
+// reads base, increment, delay, and movetime in seconds from the query, nil when the game is untimed
+func parseTimeControl(c *gin.Context) (*TimeControl, error) {
+    values := map[string]time.Duration{}
+
+    for _, name := range []string{"base", "increment", "delay", "movetime"} {
+        value := c.Query(name)
+        if value == "" {
+            continue
+        }
+
+        seconds, err := strconv.ParseFloat(value, 64)
+        if err != nil || seconds < 0 {
+            return nil, fmt.Errorf("invalid %s", name)
+        }
+
+        values[name] = time.Duration(seconds * float64(time.Second))
+    }
+
+    if values["base"] <= 0 && values["movetime"] <= 0 {
+        return nil, nil
+    }
+
+    return &TimeControl{
+        Base: values["base"],
+        Increment: values["increment"],
+        Delay: values["delay"],
+        MoveTime: values["movetime"],
+    }, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_Clock_Fischer(t *testing.T) {
+    white := 0
+    black := 1
+    now := time.Now()
+
+    clock := newClock(TimeControl{Base: time.Minute, Increment: 2 * time.Second}, 2)
+    assert.Equal(t, -1, clock.flagged(now))
+
+    clock.press(white, black, now) // the first move is free
+    assert.Equal(t, []int64{62000, 60000}, clock.data(now).Remaining)
+    assert.Equal(t, black, clock.data(now).Running)
+
+    now = now.Add(10 * time.Second)
+    assert.Equal(t, []int64{62000, 50000}, clock.data(now).Remaining)
+
+    clock.press(black, white, now)
+    assert.Equal(t, []int64{62000, 52000}, clock.data(now).Remaining)
+
+    now = now.Add(62 * time.Second)
+    assert.Equal(t, white, clock.flagged(now))
+}
+
+func Test_Clock_Bronstein(t *testing.T) {
+    white := 0
+    black := 1
+    now := time.Now()
+
+    clock := newClock(TimeControl{Base: time.Minute, Delay: 5 * time.Second}, 2)
+    clock.press(white, black, now)
+
+    now = now.Add(3 * time.Second)
+    clock.press(black, white, now) // used less than the delay
+    assert.Equal(t, []int64{60000, 60000}, clock.data(now).Remaining)
+
+    now = now.Add(8 * time.Second)
+    clock.press(white, black, now) // used more than the delay
+    assert.Equal(t, []int64{57000, 60000}, clock.data(now).Remaining)
+}
+
+func Test_Clock_MoveTime(t *testing.T) {
+    red := 0
+    blue := 1
+    yellow := 2
+    now := time.Now()
+
+    clock := newClock(TimeControl{MoveTime: 10 * time.Second}, 4)
+    clock.press(red, blue, now)
+
+    now = now.Add(9 * time.Second)
+    clock.press(blue, yellow, now)
+    assert.Equal(t, []int64{10000, 10000, 10000, 10000}, clock.data(now).Remaining)
+
+    now = now.Add(11 * time.Second)
+    assert.Equal(t, yellow, clock.flagged(now))
+}
+
+func Test_Clock_Restore(t *testing.T) {
+    white := 0
+    black := 1
+    now := time.Now()
+
+    clock := newClock(TimeControl{Base: time.Minute}, 2)
+    clock.press(white, black, now)
+    clock.save(1)
+
+    now = now.Add(20 * time.Second)
+    clock.press(black, white, now)
+    clock.save(2)
+
+    now = now.Add(5 * time.Second)
+    err := clock.restore(1, black, now)
+    assert.Nil(t, err)
+    assert.Equal(t, []int64{60000, 60000}, clock.data(now).Remaining)
+    assert.Equal(t, black, clock.data(now).Running)
+
+    err = clock.restore(2, white, now)
+    assert.Nil(t, err)
+    assert.Equal(t, []int64{60000, 40000}, clock.data(now).Remaining)
+
+    err = clock.restore(0, white, now)
+    assert.Nil(t, err)
+    assert.Equal(t, -1, clock.data(now).Running)
+
+    clock.save(0) // a new move after undoing everything replaces the redo states
+    err = clock.restore(2, white, now)
+    assert.NotNil(t, err)
+}
+
+func Test_Clock_BotTimeLimit(t *testing.T) {
+    white := 0
+    black := 1
+    now := time.Now()
+
+    clock := newClock(TimeControl{Base: time.Minute, Increment: 2 * time.Second}, 2)
+    assert.Equal(t, 4 * time.Second, clock.botTimeLimit(black, now))
+
+    clock.press(white, black, now)
+    now = now.Add(30 * time.Second)
+    assert.Equal(t, 3 * time.Second, clock.botTimeLimit(black, now))
+
+    // a nearly flagged bot doesn't spend the increment it doesn't have yet
+    now = now.Add(29 * time.Second)
+    assert.Equal(t, 900 * time.Millisecond, clock.botTimeLimit(black, now))
+
+    clock = newClock(TimeControl{MoveTime: 10 * time.Second}, 2)
+    assert.Equal(t, 9 * time.Second, clock.botTimeLimit(black, now))
+}
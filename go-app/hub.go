@@ -4,6 +4,8 @@ import (
     "encoding/json"
     "fmt"
     "go-app/chess"
+    "sync"
+    "time"
 )
 
 type Message struct {
@@ -17,6 +19,8 @@ type MoveData struct {
     XTo int
     YTo int
     Promotion string
+    Drop string // letter of the piece dropped from the reserve, the from square is ignored
+    HistoryLength *int // set by the bot, a move searched on an earlier position is dropped
 }
 
 type ViewData struct {
@@ -24,6 +28,35 @@ type ViewData struct {
     Y int
 }
 
+type ColorData struct {
+    Color int
+}
+
+// sent to the partner hub of a bughouse board
+type TransferData struct {
+    Captures []*chess.CaptureData // pieces captured on the sending board
+    GameOver bool
+    Winner int // winner on the sending board, -1 for a draw
+}
+
+// owned by the receiving hub, pushing never blocks so two linked hubs can't wait on each other or drop a transfer
+type TransferQueue struct {
+    mutex sync.Mutex
+    pending []*TransferData
+    ready chan struct{} // holds one signal while transfers are pending
+}
+
+// shared by two linked bughouse hubs, once one of them stops the other one stops too
+type HubLink struct {
+    once sync.Once
+    done chan struct{}
+}
+
+type BoardStateData struct {
+    *chess.BoardData
+    Clock *ClockData // nil when the game is untimed
+}
+
 type Hub struct {
     botColors []int
     clients map[Client]bool
@@ -32,27 +65,28 @@ type Hub struct {
     send chan *ClientMessage
     capacity int
     game chess.Game
+    clock *Clock
+    partner *Hub // the linked bughouse board, nil otherwise
+    transfers *TransferQueue // captures and results from the partner hub
+    link *HubLink
 }
 
-func newTwoPlayerHubWithBot() *Hub {
-    black := 1
-
-    game, err := chess.NewSimpleGame()
-    if err != nil {
-        panic(err)
-    }
-
+func newHub(game chess.Game, capacity int, botColors []int) *Hub {
     hub := &Hub{
-        botColors:  []int{black},
+        botColors:  botColors,
         clients:    make(map[Client]bool),
         register:   make(chan Client),
         unregister: make(chan Client),
         send:       make(chan *ClientMessage),
-        capacity:   2,
+        capacity:   capacity,
         game:       game,
     }
 
-    botClient, err := newBotClient(hub, game)
+    if len(botColors) == 0 {
+        return hub
+    }
+
+    botClient, err := newBotClient(hub)
     if err != nil {
         panic(err)
     }
@@ -64,98 +98,92 @@ func newTwoPlayerHubWithBot() *Hub {
     return hub
 }
 
-func newSmallTwoPlayerHubWithBot() *Hub {
-    black := 1
-
-    game, err := chess.NewSimpleSmallGame()
-    if err != nil {
-        panic(err)
+func newTransferQueue() *TransferQueue {
+    return &TransferQueue{
+        pending: []*TransferData{},
+        ready:   make(chan struct{}, 1),
     }
+}
 
-    hub := &Hub{
-        botColors:  []int{black},
-        clients:    make(map[Client]bool),
-        register:   make(chan Client),
-        unregister: make(chan Client),
-        send:       make(chan *ClientMessage),
-        capacity:   2,
-        game:       game,
-    }
+func (q *TransferQueue) push(transfer *TransferData) {
+    q.mutex.Lock()
+    q.pending = append(q.pending, transfer)
+    q.mutex.Unlock()
 
-    botClient, err := newBotClient(hub, game)
-    if err != nil {
-        panic(err)
+    select {
+    case q.ready <- struct{}{}:
+    default:
     }
+}
 
-    hub.handleClientJoin(botClient)
+// takes every pending transfer in the order they were pushed
+func (q *TransferQueue) take() []*TransferData {
+    q.mutex.Lock()
+    defer q.mutex.Unlock()
 
-    go botClient.run()
+    transfers := q.pending
+    q.pending = []*TransferData{}
+    return transfers
+}
 
-    return hub
+func newHubLink() *HubLink {
+    return &HubLink{
+        done: make(chan struct{}),
+    }
 }
 
-func newFourPlayerHubWithBot() *Hub {
+func (l *HubLink) stop() {
+    l.once.Do(func() {
+        close(l.done)
+    })
+}
+
+func newTwoPlayerHubWithBot() *Hub {
     black := 1
-    red := 2
-    blue := 3
 
-    game, err := chess.NewSimpleFourPlayerGame()
+    game, err := chess.NewSimpleGame()
     if err != nil {
         panic(err)
     }
 
-    hub := &Hub{
-        botColors:  []int{black, red, blue},
-        clients:    make(map[Client]bool),
-        register:   make(chan Client),
-        unregister: make(chan Client),
-        send:       make(chan *ClientMessage),
-        capacity:   4,
-        game:       game,
-    }
+    return newHub(game, 2, []int{black})
+}
+
+func newSmallTwoPlayerHubWithBot() *Hub {
+    black := 1
 
-    botClient, err := newBotClient(hub, game)
+    game, err := chess.NewSimpleSmallGame()
     if err != nil {
         panic(err)
     }
 
-    hub.handleClientJoin(botClient)
-
-    go botClient.run()
-
-    return hub
+    return newHub(game, 2, []int{black})
 }
 
-func newSmallFourPlayerHubWithBot() *Hub {
+func newFourPlayerHubWithBot() *Hub {
     black := 1
     red := 2
     blue := 3
 
-    game, err := chess.NewSimpleSmallFourPlayerGame()
+    game, err := chess.NewSimpleFourPlayerGame()
     if err != nil {
         panic(err)
     }
 
-    hub := &Hub{
-        botColors:  []int{black, red, blue},
-        clients:    make(map[Client]bool),
-        register:   make(chan Client),
-        unregister: make(chan Client),
-        send:       make(chan *ClientMessage),
-        capacity:   4,
-        game:       game,
-    }
+    return newHub(game, 4, []int{black, red, blue})
+}
+
+func newSmallFourPlayerHubWithBot() *Hub {
+    black := 1
+    red := 2
+    blue := 3
 
-    botClient, err := newBotClient(hub, game)
+    game, err := chess.NewSimpleSmallFourPlayerGame()
     if err != nil {
         panic(err)
     }
 
-    hub.handleClientJoin(botClient)
-
-    go botClient.run()
-
-    return hub
+    return newHub(game, 4, []int{black, red, blue})
 }
 
 func newTwoPlayerHub() *Hub {
@@ -164,15 +192,7 @@ func newTwoPlayerHub() *Hub {
         panic(err)
     }
 
-    return &Hub{
-        botColors:  []int{},
-        clients:    make(map[Client]bool),
-        register:   make(chan Client),
-        unregister: make(chan Client),
-        send:       make(chan *ClientMessage),
-        capacity:   2,
-        game:       game,
-    }
+    return newHub(game, 2, []int{})
 }
 
 func newSmallTwoPlayerHub() *Hub {
@@ -181,15 +201,7 @@ func newSmallTwoPlayerHub() *Hub {
         panic(err)
     }
 
-    return &Hub{
-        botColors:  []int{},
-        clients:    make(map[Client]bool),
-        register:   make(chan Client),
-        unregister: make(chan Client),
-        send:       make(chan *ClientMessage),
-        capacity:   2,
-        game:       game,
-    }
+    return newHub(game, 2, []int{})
 }
 
 func newFourPlayerHub() *Hub {
@@ -198,15 +210,7 @@ func newFourPlayerHub() *Hub {
         panic(err)
     }
 
-    return &Hub{
-        botColors:  []int{},
-        clients:    make(map[Client]bool),
-        register:   make(chan Client),
-        unregister: make(chan Client),
-        send:       make(chan *ClientMessage),
-        capacity:   4,
-        game:       game,
-    }
+    return newHub(game, 4, []int{})
 }
 
 func newSmallFourPlayerHub() *Hub {
@@ -215,18 +219,90 @@ func newSmallFourPlayerHub() *Hub {
         panic(err)
     }
 
-    return &Hub{
-        botColors:  []int{},
-        clients:    make(map[Client]bool),
-        register:   make(chan Client),
-        unregister: make(chan Client),
-        send:       make(chan *ClientMessage),
-        capacity:   4,
-        game:       game,
+    return newHub(game, 4, []int{})
+}
+
+// every color except the first one in the turn order is played by the bot
+func newVariantHub(definition *chess.VariantDefinition, withBot bool) (*Hub, error) {
+    game, err := chess.NewGameFromVariant(definition)
+    if err != nil {
+        return nil, err
+    }
+
+    state, err := game.State()
+    if err != nil {
+        return nil, err
+    }
+
+    botColors := []int{}
+    if withBot {
+        for color := 0; color < definition.Players; color++ {
+            if color != state.CurrentPlayer {
+                botColors = append(botColors, color)
+            }
+        }
+    }
+
+    if definition.Bughouse {
+        return nil, fmt.Errorf("bughouse needs two linked boards")
     }
+
+    return newHub(game, definition.Players, botColors), nil
+}
+
+// partners play opposite colors, white on the first board plays with black on the second board
+func newBughouseHubs(definition *chess.VariantDefinition) (*Hub, *Hub, error) {
+    if !definition.Bughouse {
+        return nil, nil, fmt.Errorf("not a bughouse variant")
+    }
+
+    link := newHubLink()
+    hubs := []*Hub{}
+    for i := 0; i < 2; i++ {
+        game, err := chess.NewGameFromVariant(definition)
+        if err != nil {
+            return nil, nil, err
+        }
+
+        hub := newHub(game, definition.Players, []int{})
+        hub.transfers = newTransferQueue()
+        hub.link = link
+        hubs = append(hubs, hub)
+    }
+
+    hubs[0].partner = hubs[1]
+    hubs[1].partner = hubs[0]
+
+    return hubs[0], hubs[1], nil
+}
+
+func (h *Hub) setTimeControl(timeControl *TimeControl) {
+    if timeControl == nil {
+        return
+    }
+
+    h.clock = newClock(*timeControl, h.capacity)
 }
 
 func (h *Hub) run() {
+    var tick <-chan time.Time
+    if h.clock != nil {
+        ticker := time.NewTicker(clockTickPeriod)
+        defer ticker.Stop()
+        tick = ticker.C
+    }
+
+    // a linked hub stops once its partner stops, even if nobody joined it
+    var transfersReady <-chan struct{}
+    if h.transfers != nil {
+        transfersReady = h.transfers.ready
+    }
+    var linkDone <-chan struct{}
+    if h.link != nil {
+        defer h.link.stop()
+        linkDone = h.link.done
+    }
+
     for {
         select {
         case client := <-h.register:
@@ -245,6 +321,13 @@ func (h *Hub) run() {
             h.handleMessage(
                 clientMessage.message,
             )
+        case <-transfersReady:
+            h.handleTransfers()
+        case <-linkDone:
+            h.close()
+            return
+        case <-tick:
+            h.handleTimeout()
         }
     }
 }
@@ -309,6 +392,9 @@ func (h *Hub) handleMessage(unmarshalledMessage []byte) {
         return
     }
 
+    // a move that arrives after the flag fell is too late
+    h.handleTimeout()
+
     if message.Type == "move" {
         h.handleMoveMessage(message.Data)
     } else if message.Type == "view" {
@@ -317,6 +403,10 @@ func (h *Hub) handleMessage(unmarshalledMessage []byte) {
         h.handleUndoMessage()
     } else if message.Type == "redo" {
         h.handleRedoMessage()
+    } else if message.Type == "resign" {
+        h.handleResignMessage(message.Data)
+    } else if message.Type == "draw" {
+        h.handleDrawMessage(message.Data)
     } else {
         fmt.Println("unknown message type")
     }
@@ -330,18 +420,36 @@ func (h *Hub) handleMoveMessage(messageData json.RawMessage) {
         return
     }
 
-    err = h.game.Execute(
-        moveData.XFrom,
-        moveData.YFrom,
-        moveData.XTo,
-        moveData.YTo,
-        moveData.Promotion,
-    )
+    if moveData.HistoryLength != nil && *moveData.HistoryLength != h.game.HistoryLength() {
+        fmt.Println("move is out of date")
+        return
+    }
+
+    state, err := h.game.State()
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    if moveData.Drop != "" {
+        err = h.game.Drop(moveData.Drop, moveData.XTo, moveData.YTo)
+    } else {
+        err = h.game.Execute(
+            moveData.XFrom,
+            moveData.YFrom,
+            moveData.XTo,
+            moveData.YTo,
+            moveData.Promotion,
+        )
+    }
     if err != nil {
         fmt.Println(err)
         return
     }
 
+    h.pressClock(state.CurrentPlayer)
+    h.sendTransfer(h.captures())
+
     message, err := h.createBoardStateMessage()
     if err != nil {
         fmt.Println("error creating state message")
@@ -377,8 +485,9 @@ func (h *Hub) handleViewMessage(messageData json.RawMessage) {
     h.broadcastMessage(message)
 }
 
+// moves can't be taken back once the captures went to the partner board
 func (h *Hub) handleUndoMessage() {
-    if !h.playerTurn() {
+    if h.partner != nil || !h.playerTurn() {
         return
     }
 
@@ -394,6 +503,8 @@ func (h *Hub) handleUndoMessage() {
         }
     }
 
+    h.restoreClock()
+
     message, err := h.createBoardStateMessage()
     if err != nil {
         fmt.Println("error creating state message")
@@ -404,7 +515,7 @@ func (h *Hub) handleUndoMessage() {
 }
 
 func (h *Hub) handleRedoMessage() {
-    if !h.playerTurn() {
+    if h.partner != nil || !h.playerTurn() {
         return
     }
 
@@ -420,6 +531,8 @@ func (h *Hub) handleRedoMessage() {
         }
     }
 
+    h.restoreClock()
+
     message, err := h.createBoardStateMessage()
     if err != nil {
         fmt.Println("error creating state message")
@@ -429,6 +542,295 @@ func (h *Hub) handleRedoMessage() {
     h.broadcastMessage(message)
 }
 
+func (h *Hub) handleResignMessage(messageData json.RawMessage) {
+    var colorData ColorData
+    err := json.Unmarshal(messageData, &colorData)
+    if err != nil {
+        fmt.Println("error unmarshalling color data")
+        return
+    }
+
+    if h.botColor(colorData.Color) {
+        return
+    }
+
+    err = h.game.Resign(colorData.Color)
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    h.switchClock()
+    h.sendTransfer(nil)
+
+    message, err := h.createBoardStateMessage()
+    if err != nil {
+        fmt.Println("error creating state message")
+        return
+    }
+
+    h.broadcastMessage(message)
+}
+
+// accepts a pending draw offer, otherwise offers a draw
+func (h *Hub) handleDrawMessage(messageData json.RawMessage) {
+    var colorData ColorData
+    err := json.Unmarshal(messageData, &colorData)
+    if err != nil {
+        fmt.Println("error unmarshalling color data")
+        return
+    }
+
+    if h.botColor(colorData.Color) {
+        return
+    }
+
+    err = h.game.AcceptDraw(colorData.Color)
+    if err != nil {
+        err = h.game.OfferDraw(colorData.Color)
+    }
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    h.switchClock()
+    h.sendTransfer(nil)
+
+    message, err := h.createBoardStateMessage()
+    if err != nil {
+        fmt.Println("error creating state message")
+        return
+    }
+
+    h.broadcastMessage(message)
+}
+
+// a player who runs out of time loses, in four player games they are eliminated
+func (h *Hub) handleTimeout() {
+    if h.clock == nil {
+        return
+    }
+
+    color := h.clock.flagged(time.Now())
+    if color < 0 {
+        return
+    }
+
+    err := h.game.Resign(color)
+    if err != nil {
+        fmt.Println(err)
+        h.clock.stop(time.Now())
+        return
+    }
+
+    h.switchClock()
+    h.sendTransfer(nil)
+
+    message, err := h.createBoardStateMessage()
+    if err != nil {
+        fmt.Println("error creating state message")
+        return
+    }
+
+    h.broadcastMessage(message)
+}
+
+// hands the captured pieces to the partner board, and ends it once this board is over
+func (h *Hub) sendTransfer(captures []*chess.CaptureData) {
+    if h.partner == nil {
+        return
+    }
+
+    state, err := h.game.State()
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    if len(captures) == 0 && !state.GameOver {
+        return
+    }
+
+    transfer := &TransferData{
+        Captures: captures,
+        GameOver: state.GameOver,
+        Winner: state.WinningPlayer,
+    }
+
+    h.partner.transfers.push(transfer)
+}
+
+// pieces captured by the last move, only bughouse games hand them over
+func (h *Hub) captures() []*chess.CaptureData {
+    game, ok := h.game.(chess.BughouseGame)
+    if !ok {
+        return nil
+    }
+
+    return game.Captures()
+}
+
+func (h *Hub) handleTransfers() {
+    for _, transfer := range h.transfers.take() {
+        h.handleTransfer(transfer)
+    }
+}
+
+// the partner board ended or captured pieces for this board
+func (h *Hub) handleTransfer(transfer *TransferData) {
+    game, ok := h.game.(chess.BughouseGame)
+    if !ok {
+        fmt.Println("not a bughouse game")
+        return
+    }
+
+    state, err := game.State()
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    if state.GameOver {
+        return
+    }
+
+    for _, capture := range transfer.Captures {
+        err = game.Deposit(capture.C, capture.T)
+        if err != nil {
+            fmt.Println(err)
+        }
+    }
+
+    if transfer.GameOver {
+        // the winner's partner plays the other color on this board
+        winner := -1
+        if transfer.Winner >= 0 {
+            winner = 1 - transfer.Winner
+        }
+
+        err = game.End(winner)
+        if err != nil {
+            fmt.Println(err)
+        }
+
+        h.switchClock()
+    }
+
+    message, err := h.createBoardStateMessage()
+    if err != nil {
+        fmt.Println("error creating state message")
+        return
+    }
+
+    h.broadcastMessage(message)
+}
+
+// called after a move is accepted
+func (h *Hub) pressClock(color int) {
+    if h.clock == nil {
+        return
+    }
+
+    state, err := h.game.State()
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    now := time.Now()
+    if state.GameOver {
+        h.clock.stop(now)
+    } else {
+        h.clock.press(color, state.CurrentPlayer, now)
+    }
+
+    h.clock.save(h.game.HistoryLength())
+}
+
+// called after resignations and draw offers, the clock follows the current player without giving time back
+func (h *Hub) switchClock() {
+    if h.clock == nil {
+        return
+    }
+
+    state, err := h.game.State()
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    now := time.Now()
+    if state.GameOver {
+        h.clock.stop(now)
+    } else if h.clock.running >= 0 {
+        h.clock.start(state.CurrentPlayer, now)
+    }
+
+    h.clock.save(h.game.HistoryLength())
+}
+
+// called after undo and redo
+func (h *Hub) restoreClock() {
+    if h.clock == nil {
+        return
+    }
+
+    state, err := h.game.State()
+    if err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    running := state.CurrentPlayer
+    if state.GameOver {
+        running = -1
+    }
+
+    err = h.clock.restore(h.game.HistoryLength(), running, time.Now())
+    if err != nil {
+        fmt.Println(err)
+    }
+}
+
+// a copy of the game while a bot is to move, nil otherwise
+func (h *Hub) createBotRequest() (*BotRequest, error) {
+    state, err := h.game.State()
+    if err != nil {
+        return nil, err
+    }
+
+    if state.GameOver || !h.botColor(state.CurrentPlayer) {
+        return nil, nil
+    }
+
+    game, err := h.game.Copy()
+    if err != nil {
+        return nil, err
+    }
+
+    timeLimit := botTimeLimit
+    if h.clock != nil {
+        timeLimit = h.clock.botTimeLimit(state.CurrentPlayer, time.Now())
+    }
+
+    return &BotRequest{
+        game: game,
+        timeLimit: timeLimit,
+        historyLength: h.game.HistoryLength(),
+    }, nil
+}
+
+func (h *Hub) botColor(color int) bool {
+    for _, botColor := range h.botColors {
+        if botColor == color {
+            return true
+        }
+    }
+
+    return false
+}
+
 func (h *Hub) playerTurn() bool {
     state, err := h.game.State()
     if err != nil {
@@ -454,7 +856,14 @@ func (h *Hub) createBoardStateMessage() ([]byte, error) {
         return nil, err
     }
 
-    marshalledState, err := json.Marshal(state)
+    boardState := BoardStateData{
+        BoardData: state,
+    }
+    if h.clock != nil {
+        boardState.Clock = h.clock.data(time.Now())
+    }
+
+    marshalledState, err := json.Marshal(boardState)
     if err != nil {
         fmt.Println("error marshalling board state")
         return nil, err
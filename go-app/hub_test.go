@@ -0,0 +1,157 @@
+package main
+
+import (
+    "encoding/json"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func sendHubMessage(t *testing.T, hub *Hub, messageType string, data any) {
+    messageData, err := json.Marshal(data)
+    assert.Nil(t, err)
+
+    message, err := json.Marshal(Message{Type: messageType, Data: messageData})
+    assert.Nil(t, err)
+
+    hub.handleMessage(message)
+}
+
+func Test_Hub_Bughouse(t *testing.T) {
+    white := 0
+    black := 1
+
+    definition, err := loadVariant("bughouse")
+    assert.Nil(t, err)
+
+    _, err = newVariantHub(definition, false)
+    assert.NotNil(t, err)
+
+    hub, partner, err := newBughouseHubs(definition)
+    assert.Nil(t, err)
+
+    moves := []MoveData{{XFrom: 4, YFrom: 6, XTo: 4, YTo: 4}, {XFrom: 3, YFrom: 1, XTo: 3, YTo: 3}}
+    for _, move := range moves {
+        sendHubMessage(t, hub, "move", move)
+    }
+    assert.Empty(t, partner.transfers.take()) // nothing was captured yet
+
+    sendHubMessage(t, hub, "move", MoveData{XFrom: 4, YFrom: 4, XTo: 3, YTo: 3})
+    partner.handleTransfers()
+
+    state, err := partner.game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, []map[string]int{{}, {"P": 1}}, state.Reserves)
+
+    // captures can't be taken back from the partner board
+    sendHubMessage(t, hub, "undo", struct{}{})
+    assert.Equal(t, 3, hub.game.HistoryLength())
+
+    sendHubMessage(t, hub, "resign", ColorData{Color: black})
+    partner.handleTransfers()
+
+    state, err = partner.game.State()
+    assert.Nil(t, err)
+    assert.True(t, state.GameOver)
+    assert.Equal(t, black, state.WinningPlayer) // the partner of white on the first board
+    assert.Empty(t, hub.transfers.take())
+
+    state, err = hub.game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, white, state.WinningPlayer)
+}
+
+func Test_Hub_BughouseTransfers(t *testing.T) {
+    queue := newTransferQueue()
+    for i := 0; i < 100; i++ {
+        queue.push(&TransferData{Winner: i})
+    }
+    <-queue.ready
+
+    transfers := queue.take()
+    assert.Equal(t, 100, len(transfers)) // nothing is dropped while the receiver is busy
+    assert.Equal(t, 99, transfers[99].Winner)
+    assert.Empty(t, queue.take())
+}
+
+func Test_Hub_BughouseLink(t *testing.T) {
+    definition, err := loadVariant("bughouse")
+    assert.Nil(t, err)
+
+    hub, partner, err := newBughouseHubs(definition)
+    assert.Nil(t, err)
+
+    // nobody joined the partner board, it stops together with the first board
+    stopped := make(chan bool)
+    go func() {
+        partner.run()
+        stopped <- true
+    }()
+    hub.link.stop()
+
+    select {
+    case <-stopped:
+    case <-time.After(time.Second):
+        t.Fatal("partner hub didn't stop")
+    }
+}
+
+func Test_Hub_BotRequest(t *testing.T) {
+    white := 0
+    black := 1
+
+    hub := newTwoPlayerHub()
+    hub.botColors = []int{black}
+    hub.setTimeControl(&TimeControl{Base: time.Minute})
+
+    request, err := hub.createBotRequest()
+    assert.Nil(t, err)
+    assert.Nil(t, request) // white is a player
+
+    sendHubMessage(t, hub, "move", MoveData{XFrom: 4, YFrom: 6, XTo: 4, YTo: 4})
+
+    request, err = hub.createBotRequest()
+    assert.Nil(t, err)
+    assert.NotNil(t, request)
+    assert.InDelta(t, 2 * time.Second, request.timeLimit, float64(100 * time.Millisecond))
+
+    // the hub keeps changing its game while the bot searches the copy
+    err = hub.game.Resign(black)
+    assert.Nil(t, err)
+
+    state, err := request.game.State()
+    assert.Nil(t, err)
+    assert.False(t, state.GameOver)
+    assert.Equal(t, black, state.CurrentPlayer)
+
+    state, err = hub.game.State()
+    assert.Nil(t, err)
+    assert.Equal(t, white, state.WinningPlayer)
+}
+
+func Test_Hub_BotRequestOnlyForBoardState(t *testing.T) {
+    black := 1
+
+    hub := newTwoPlayerHub()
+    hub.botColors = []int{black}
+    botClient, err := newBotClient(hub)
+    assert.Nil(t, err)
+    hub.handleClientJoin(botClient)
+    assert.Equal(t, 0, len(botClient.requests)) // white is a player
+
+    sendHubMessage(t, hub, "move", MoveData{XFrom: 4, YFrom: 6, XTo: 4, YTo: 4})
+    sendHubMessage(t, hub, "view", ViewData{X: 4, Y: 1})
+    assert.Equal(t, 1, len(botClient.requests))
+
+    request := <-botClient.requests
+    assert.Equal(t, 1, request.historyLength)
+
+    // a move searched on an earlier position is dropped
+    stale := 0
+    sendHubMessage(t, hub, "move", MoveData{XFrom: 4, YFrom: 1, XTo: 4, YTo: 3, HistoryLength: &stale})
+    assert.Equal(t, 1, hub.game.HistoryLength())
+
+    sendHubMessage(t, hub, "move", MoveData{XFrom: 4, YFrom: 1, XTo: 4, YTo: 3, HistoryLength: &request.historyLength})
+    assert.Equal(t, 2, hub.game.HistoryLength())
+}
@@ -4,12 +4,18 @@ import (
 	"fmt"
 	"net/http"
     "math/rand"
+    "os"
+    "path/filepath"
+    "strconv"
+
+    "go-app/chess"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
 const roomIdLength = 4
+const variantsDirectory = "variants"
 
 var newline = []byte{'\n'}
 var space = []byte{' '}
@@ -46,6 +52,37 @@ func createRoomId(hubs map[string]*Hub) (string, error) {
     return roomId, nil
 }
 
+func loadVariant(name string) (*chess.VariantDefinition, error) {
+    for _, r := range name {
+        if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+            return nil, fmt.Errorf("invalid variant name")
+        }
+    }
+
+    data, err := os.ReadFile(filepath.Join(variantsDirectory, name + ".json"))
+    if err != nil {
+        return nil, err
+    }
+
+    return chess.ParseVariant(data)
+}
+
+// a position in the query overrides the chess960 position of the variant
+func setChess960Position(c *gin.Context, definition *chess.VariantDefinition) error {
+    value := c.Query("position")
+    if value == "" {
+        return nil
+    }
+
+    position, err := strconv.Atoi(value)
+    if err != nil || !definition.Chess960 {
+        return fmt.Errorf("invalid position %s", value)
+    }
+    definition.Chess960Position = position
+
+    return definition.Validate()
+}
+
 func startClient(c *gin.Context, hub *Hub, conn *websocket.Conn) {
     conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
     if err != nil {
@@ -65,145 +102,143 @@ func startClient(c *gin.Context, hub *Hub, conn *websocket.Conn) {
     go playerClient.readLoop()
 }
 
-func main() {
-    var hubs = make(map[string]*Hub)
+// a bad time control is refused before any hub exists, the client gets an answer instead of a hanging websocket
+func startHubs(c *gin.Context, hubs map[string]*Hub, createHubs func() ([]*Hub, error)) {
+    timeControl, err := parseTimeControl(c)
+    if err != nil {
+        fmt.Println("invalid time control: ", err)
+        c.String(http.StatusBadRequest, "invalid time control: %v", err)
+        return
+    }
 
-	router := gin.Default()
-    router.GET("/ws/twobot", func(c *gin.Context) {
+    newHubs, err := createHubs()
+    if err != nil {
+        fmt.Println("couldn't create game: ", err)
+        c.String(http.StatusBadRequest, "couldn't create game: %v", err)
+        return
+    }
+
+    // linked hubs are joined through their own game ids
+    roomIds := []string{}
+    for _, hub := range newHubs {
         roomId, err := createRoomId(hubs)
         if err != nil {
             fmt.Println("couldn't create game")
+            for _, roomId := range roomIds {
+                delete(hubs, roomId)
+            }
             return
         }
-        hub := newTwoPlayerHubWithBot()
+        hub.setTimeControl(timeControl)
         hubs[roomId] = hub
-        go func() {
+        roomIds = append(roomIds, roomId)
+    }
+
+    for i, hub := range newHubs {
+        go func(hub *Hub, roomId string) {
             hub.run()
             fmt.Println("shutting down hub")
             delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
+        }(hub, roomIds[i])
+    }
+    fmt.Println("new connection, new game, gameIds: ", roomIds)
 
-        startClient(c, hub, nil)
-    })
-    router.GET("/ws/smalltwobot", func(c *gin.Context) {
-        roomId, err := createRoomId(hubs)
+    startClient(c, newHubs[0], nil)
+}
+
+func startHub(c *gin.Context, hubs map[string]*Hub, createHub func() (*Hub, error)) {
+    startHubs(c, hubs, func() ([]*Hub, error) {
+        hub, err := createHub()
         if err != nil {
-            fmt.Println("couldn't create game")
-            return
+            return nil, err
         }
-        hub := newSmallTwoPlayerHubWithBot()
-        hubs[roomId] = hub
-        go func() {
-            hub.run()
-            fmt.Println("shutting down hub")
-            delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
 
-        startClient(c, hub, nil)
+        return []*Hub{hub}, nil
     })
-    router.GET("/ws/fourbot", func(c *gin.Context) {
-        roomId, err := createRoomId(hubs)
-        if err != nil {
-            fmt.Println("couldn't create game")
-            return
-        }
-        hub := newFourPlayerHubWithBot()
-        hubs[roomId] = hub
-        go func() {
-            hub.run()
-            fmt.Println("shutting down hub")
-            delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
+}
 
-        startClient(c, hub, nil)
+func main() {
+    var hubs = make(map[string]*Hub)
+
+	router := gin.Default()
+    router.GET("/ws/twobot", func(c *gin.Context) {
+        startHub(c, hubs, func() (*Hub, error) {
+            return newTwoPlayerHubWithBot(), nil
+        })
+    })
+    router.GET("/ws/smalltwobot", func(c *gin.Context) {
+        startHub(c, hubs, func() (*Hub, error) {
+            return newSmallTwoPlayerHubWithBot(), nil
+        })
+    })
+    router.GET("/ws/fourbot", func(c *gin.Context) {
+        startHub(c, hubs, func() (*Hub, error) {
+            return newFourPlayerHubWithBot(), nil
+        })
     })
     router.GET("/ws/smallfourbot", func(c *gin.Context) {
-        roomId, err := createRoomId(hubs)
-        if err != nil {
-            fmt.Println("couldn't create game")
-            return
-        }
-        hub := newSmallFourPlayerHubWithBot()
-        hubs[roomId] = hub
-        go func() {
-            hub.run()
-            fmt.Println("shutting down hub")
-            delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
-
-        startClient(c, hub, nil)
+        startHub(c, hubs, func() (*Hub, error) {
+            return newSmallFourPlayerHubWithBot(), nil
+        })
     })
     router.GET("/ws/two", func(c *gin.Context) {
-        roomId, err := createRoomId(hubs)
-        if err != nil {
-            fmt.Println("couldn't create game")
-            return
-        }
-        hub := newTwoPlayerHub()
-        hubs[roomId] = hub
-        go func() {
-            hub.run()
-            fmt.Println("shutting down hub")
-            delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
-
-        startClient(c, hub, nil)
+        startHub(c, hubs, func() (*Hub, error) {
+            return newTwoPlayerHub(), nil
+        })
     })
     router.GET("/ws/smalltwo", func(c *gin.Context) {
-        roomId, err := createRoomId(hubs)
-        if err != nil {
-            fmt.Println("couldn't create game")
-            return
-        }
-        hub := newSmallTwoPlayerHub()
-        hubs[roomId] = hub
-        go func() {
-            hub.run()
-            fmt.Println("shutting down hub")
-            delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
-
-        startClient(c, hub, nil)
+        startHub(c, hubs, func() (*Hub, error) {
+            return newSmallTwoPlayerHub(), nil
+        })
     })
     router.GET("/ws/four", func(c *gin.Context) {
-        roomId, err := createRoomId(hubs)
-        if err != nil {
-            fmt.Println("couldn't create game")
-            return
-        }
-        hub := newFourPlayerHub()
-        hubs[roomId] = hub
-        go func() {
-            hub.run()
-            fmt.Println("shutting down hub")
-            delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
-
-        startClient(c, hub, nil)
+        startHub(c, hubs, func() (*Hub, error) {
+            return newFourPlayerHub(), nil
+        })
     })
     router.GET("/ws/smallfour", func(c *gin.Context) {
-        roomId, err := createRoomId(hubs)
-        if err != nil {
-            fmt.Println("couldn't create game")
-            return
-        }
-        hub := newSmallFourPlayerHub()
-        hubs[roomId] = hub
-        go func() {
-            hub.run()
-            fmt.Println("shutting down hub")
-            delete(hubs, roomId)
-        }()
-        fmt.Println("new connection, new game, gameId: ", roomId)
-
-        startClient(c, hub, nil)
+        startHub(c, hubs, func() (*Hub, error) {
+            return newSmallFourPlayerHub(), nil
+        })
+    })
+    router.GET("/ws/variant/:name", func(c *gin.Context) {
+        startHub(c, hubs, func() (*Hub, error) {
+            definition, err := loadVariant(c.Param("name"))
+            if err != nil {
+                return nil, err
+            }
+            err = setChess960Position(c, definition)
+            if err != nil {
+                return nil, err
+            }
+            return newVariantHub(definition, false)
+        })
+    })
+    router.GET("/ws/variantbot/:name", func(c *gin.Context) {
+        startHub(c, hubs, func() (*Hub, error) {
+            definition, err := loadVariant(c.Param("name"))
+            if err != nil {
+                return nil, err
+            }
+            err = setChess960Position(c, definition)
+            if err != nil {
+                return nil, err
+            }
+            return newVariantHub(definition, true)
+        })
+    })
+    router.GET("/ws/bughouse", func(c *gin.Context) {
+        startHubs(c, hubs, func() ([]*Hub, error) {
+            definition, err := loadVariant("bughouse")
+            if err != nil {
+                return nil, err
+            }
+            hub, partner, err := newBughouseHubs(definition)
+            if err != nil {
+                return nil, err
+            }
+            return []*Hub{hub, partner}, nil
+        })
     })
     router.GET("/ws/join/:gameId", func(c *gin.Context) {
         hub, ok := hubs[c.Param("gameId")]
@@ -0,0 +1,26 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "github.com/stretchr/testify/assert"
+)
+
+func Test_Server_InvalidTimeControl(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    recorder := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(recorder)
+    c.Request = httptest.NewRequest(http.MethodGet, "/ws/two?base=abc", nil)
+
+    hubs := map[string]*Hub{}
+    startHub(c, hubs, func() (*Hub, error) {
+        t.Fatal("the hub is created after the time control is parsed")
+        return nil, nil
+    })
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Empty(t, hubs)
+}
@@ -18,6 +18,14 @@ type BoardData = {
     Check: boolean,
     Checkmate: boolean,
     Stalemate: boolean,
+    HalfMoveClock: number,
+    DrawOffers: number[],
+    Teams: number[],
+    WinningTeam: number,
+    Points: number[],
+    Ranking: number[],
+    Reserves: Record<string, number>[],
+    Clock: { Remaining: number[], Running: number } | null,
 }
 
 type MoveData = {
@@ -39,6 +47,19 @@ const createMoveMessage = (xFrom: number, yFrom: number, xTo: number, yTo: numbe
     }
 }
 
+const createDropMessage = (piece: string, xTo: number, yTo: number) : Message => {
+    return {
+        Type: 'move',
+        Data: {
+            xFrom: -1,
+            yFrom: -1,
+            xTo: xTo,
+            yTo: yTo,
+            drop: piece,
+        }
+    }
+}
+
 const createViewMessage = (x: number, y: number) : Message => {
     return {
         Type: 'view',
@@ -109,6 +130,7 @@ const ChessGame = ({ urlExtension }: { urlExtension: string }): JSX.Element => {
     const [socketUrl] = useState(`ws://localhost:8080${urlExtension}`)
     const { sendMessage, lastMessage, readyState, getWebSocket } = useWebSocket(socketUrl)
     const [promotionPiece, setPromotionPiece] = useState('Q')
+    const [dropPiece, setDropPiece] = useState('')
 
     const [boardData, setBoardData] = useState<BoardData>({
         XSize: 0,
@@ -121,6 +143,14 @@ const ChessGame = ({ urlExtension }: { urlExtension: string }): JSX.Element => {
         Check: false,
         Checkmate: false,
         Stalemate: false,
+        HalfMoveClock: 0,
+        DrawOffers: [],
+        Teams: [],
+        WinningTeam: -1,
+        Points: [],
+        Ranking: [],
+        Reserves: [],
+        Clock: null,
     })
 
     const [moveData, setMoveData] = useState<MoveData>({
@@ -151,6 +181,14 @@ const ChessGame = ({ urlExtension }: { urlExtension: string }): JSX.Element => {
     }
 
     const handleView = (x: number, y: number): void => {
+        // with a piece in hand picked the next square clicked is where it is dropped
+        if (dropPiece) {
+            const dropMessage = createDropMessage(dropPiece, x, y)
+            sendMessage(JSON.stringify(dropMessage))
+            setDropPiece('')
+            return
+        }
+
         const viewMessage = createViewMessage(x, y)
         sendMessage(JSON.stringify(viewMessage))
     }
@@ -169,6 +207,12 @@ const ChessGame = ({ urlExtension }: { urlExtension: string }): JSX.Element => {
         setPromotionPiece(e.target.value)
     }
 
+    const handleDropPiece = (e: React.ChangeEvent<HTMLSelectElement>): void => {
+        setDropPiece(e.target.value)
+    }
+
+    const reserve = boardData.Reserves[Number(boardData.CurrentPlayer)] || {}
+
     return (
         <>
             <ChessBoard
@@ -194,6 +238,16 @@ const ChessGame = ({ urlExtension }: { urlExtension: string }): JSX.Element => {
                   <option value="N">Knight</option>
                 </select>
                 <br />
+                { boardData.Reserves.length > 0 && <>
+                    <label htmlFor="drop">Drop piece: </label>
+                    <select id="drop" name="drop" value={dropPiece} onChange={handleDropPiece} >
+                      <option value="">None</option>
+                      {Object.entries(reserve).map(([piece, count]) =>
+                        <option key={piece} value={piece}>{piece} ({count})</option>
+                      )}
+                    </select>
+                    <br />
+                </> }
                 <label htmlFor="undo">Undo move: </label>
                 <button id="undo" name="undo" onClick={handleUndo} />
                 <br />